@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
+)
+
+const usage = `usage:
+  awdl-mon                             start the monitor
+  awdl-mon --observe                   watch and log without root, never changing interfaces
+  awdl-mon allow <duration> [iface...] stop guarding interfaces for a while
+  awdl-mon allow off [iface...]        guard interfaces again
+  awdl-mon measure [flags] [iface]     time how quickly an interface comes back
+  awdl-mon interfaces [-json]          list every interface and whether it is guarded`
+
+// commandDeps are what the one-shot subcommands need
+type commandDeps struct {
+	monitor *services.MonitorService
+	network ports.NetworkPort
+}
+
+// runCommand runs a one-shot subcommand instead of the TUI
+func runCommand(ctx context.Context, name string, args []string, deps commandDeps) error {
+	switch name {
+	case "allow":
+		return runAllow(ctx, args, deps.monitor)
+	case "measure":
+		return runMeasure(ctx, args, deps)
+	case "interfaces":
+		return runInterfaces(ctx, args, deps.monitor)
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n%s", name, usage)
+}
+
+// runAllow snoozes the given interfaces, or every guarded interface. A
+// running monitor picks the snooze up on its next check.
+func runAllow(ctx context.Context, args []string, monitor *services.MonitorService) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing duration\n%s", usage)
+	}
+
+	names := args[1:]
+	if len(names) == 0 {
+		names = monitor.GetConfig().Interfaces
+	}
+
+	if args[0] == "off" {
+		for _, name := range names {
+			evt, err := monitor.EndSnooze(ctx, name)
+			if err != nil {
+				return err
+			}
+			if evt != nil {
+				fmt.Println(evt.Message)
+			} else {
+				fmt.Printf("%s was not snoozed\n", name)
+			}
+		}
+		return nil
+	}
+
+	d, err := time.ParseDuration(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid duration %q, expected something like 10m", args[0])
+	}
+
+	for _, name := range names {
+		evt, err := monitor.Allow(ctx, name, d)
+		if err != nil {
+			return err
+		}
+		fmt.Println(evt.Message)
+	}
+	return nil
+}
+
+// runMeasure disables an interface repeatedly and reports how long the
+// system takes to bring it back
+func runMeasure(ctx context.Context, args []string, deps commandDeps) error {
+	flags := flag.NewFlagSet("measure", flag.ContinueOnError)
+	trials := flags.Int("n", 10, "number of trials")
+	interval := flags.Duration("interval", 10*time.Millisecond, "how often to check while waiting")
+	timeout := flags.Duration("timeout", 30*time.Second, "give up on a trial after this long")
+	asJSON := flags.Bool("json", false, "print the result as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var name string
+	if flags.NArg() > 0 {
+		name = flags.Arg(0)
+	} else if guarded := deps.monitor.GetConfig().Interfaces; len(guarded) > 0 {
+		name = guarded[0]
+	} else {
+		return errors.New("no interface given and none is guarded")
+	}
+	if deps.monitor.ObserveOnly() {
+		return fmt.Errorf("measuring disables %s: %w", name, domain.ErrObserveOnly)
+	}
+
+	measure := services.NewMeasureService(deps.network)
+	result, err := measure.Measure(ctx, name, services.MeasureOptions{
+		Trials:       *trials,
+		PollInterval: *interval,
+		Timeout:      *timeout,
+		Progress: func(trial int, latency time.Duration, ok bool) {
+			if ok {
+				fmt.Fprintf(os.Stderr, "trial %d: back after %v\n", trial, latency)
+			} else {
+				fmt.Fprintf(os.Stderr, "trial %d: not back after %v\n", trial, latency)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	}
+
+	fmt.Printf("%s: %d trials, %d came back, %d timed out\n\n", result.Interface, *trials, len(result.Samples), result.TimedOut)
+	if len(result.Samples) == 0 {
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "min\tmedian\tp95\tmax\t")
+	fmt.Fprintf(w, "%v\t%v\t%v\t%v\t\n", result.Min, result.Median, result.P95, result.Max)
+	return w.Flush()
+}
+
+// runInterfaces lists every interface of the system with its state
+func runInterfaces(ctx context.Context, args []string, monitor *services.MonitorService) error {
+	flags := flag.NewFlagSet("interfaces", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the interfaces as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ifaces, err := monitor.ListInterfaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	guarded := make(map[string]bool)
+	for _, name := range monitor.GetConfig().Interfaces {
+		guarded[name] = true
+	}
+
+	if *asJSON {
+		type entry struct {
+			domain.Interface
+			Guarded bool
+		}
+		entries := make([]entry, len(ifaces))
+		for i, iface := range ifaces {
+			entries[i] = entry{Interface: iface, Guarded: guarded[iface.Name]}
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATUS\tGUARDED\tMAC\tMTU")
+	for _, iface := range ifaces {
+		mark := ""
+		if guarded[iface.Name] {
+			mark = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", iface.Name, iface.Status, mark, iface.Ether, iface.MTU)
+	}
+	return w.Flush()
+}
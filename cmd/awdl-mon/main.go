@@ -1,89 +1,287 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"syscall"
 	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/configuration"
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/filesystem"
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/helper"
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/persistence"
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/system"
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/ui"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == helperCommand {
+		if err := runHelper(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "helper: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	args, observeOnly := splitObserveFlag(os.Args[1:])
+
 	systemAdapter := system.NewSystemAdapter()
-	if !systemAdapter.HasElevatedPrivileges() {
+	if !observeOnly && !systemAdapter.HasElevatedPrivileges() {
 		fmt.Println("Error: This application requires elevated privileges (root/sudo) to manage network interfaces.")
+		fmt.Println("Run it with --observe to only watch and log, without changing anything.")
 
 		os.Exit(1)
 	}
 
+	// Started through sudo, only a helper process keeps root, to bring the
+	// guarded interfaces up and down. Everything else runs as the user, and
+	// nothing is written before privileges are dropped.
+	sudoUser, sudoed := sudoInvoker()
+
 	homeDir, err := os.UserHomeDir()
+	if sudoed {
+		homeDir, err = sudoUser.home, nil
+	}
 	if err != nil {
 		fmt.Printf("Error getting user home directory: %v\n", err)
 		os.Exit(1)
 	}
 
 	configDirPath := filepath.Join(homeDir, ".config", "awdl0-disabler")
+	appDirPath := filepath.Join(homeDir, ".awdl0-disabler")
+	logsDirPath := filepath.Join(appDirPath, "logs")
+
+	// ctx is cancelled on quit, or on a signal, to stop work in flight
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	configFilePath := filepath.Join(configDirPath, "config.json")
+	configAdapter := configuration.NewJSONConfigAdapter(configFilePath)
+
+	config, err := configAdapter.Load(ctx)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	var helperConn net.Conn
+	startHelper := newHelperStarter(config)
+	if sudoed {
+		if !observeOnly {
+			if helperConn, err = startHelper(); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := dropPrivileges(sudoUser); err != nil {
+			fmt.Printf("Error dropping privileges: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	if err := os.MkdirAll(configDirPath, 0755); err != nil {
 		fmt.Printf("Error creating config directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	logsDirPath := filepath.Join(homeDir, ".awdl0-disabler", "logs")
 	if err := os.MkdirAll(logsDirPath, 0755); err != nil {
 		fmt.Printf("Error creating logs directory: %v\n", err)
 		os.Exit(1)
 	}
 
-	configFilePath := filepath.Join(configDirPath, "config.json")
-	configAdapter := configuration.NewJSONConfigAdapter(configFilePath)
-	networkAdapter := network.NewShellNetworkAdapter()
 	loggerAdapter := filesystem.NewFileLoggerAdapter(logsDirPath)
 	repoAdapter := persistence.NewMemoryEventRepo()
 
-	if events, err := loggerAdapter.ReadEvents(time.Now()); err == nil {
-		for _, e := range events {
-			repoAdapter.Add(e)
-		}
-	} else {
+	if err := loadRecentEvents(ctx, loggerAdapter, repoAdapter, 24*time.Hour); err != nil {
 		fmt.Printf("Warning: Failed to read existing logs: %v\n", err)
 	}
 
-	config, err := configAdapter.Load()
+	networkAdapter := newNetworkAdapter(config)
+	var helperAdapter *helper.NetworkAdapter
+	if helperConn != nil {
+		helperAdapter = helper.NewNetworkAdapter(networkAdapter, helperConn).WithRestart(startHelper)
+		if err := helperAdapter.AllowInterfaces(ctx, config.Interfaces); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		networkAdapter = helperAdapter
+	}
+
+	snoozeStore := filesystem.NewJSONSnoozeStore(filepath.Join(configDirPath, "snooze.json"))
+	monitorService := services.NewMonitorService(networkAdapter, loggerAdapter, repoAdapter, config).
+		WithSnoozeStore(snoozeStore).
+		WithProcesses(system.NewPsProcessAdapter(nil))
+	if helperAdapter != nil {
+		monitorService = monitorService.WithAllowList(helperAdapter)
+	}
+	if observeOnly {
+		monitorService = monitorService.WithObserveOnly()
+	}
+
+	if len(args) > 0 {
+		deps := commandDeps{monitor: monitorService, network: networkAdapter}
+		if err := runCommand(ctx, args[0], args[1:], deps); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	keys, err := ui.NewKeyMap(config.KeyBindings)
 	if err != nil {
-		fmt.Printf("Error loading config: %v\n", err)
+		fmt.Printf("Error in key bindings: %v\n", err)
+
+		os.Exit(1)
+	}
+
+	if conflicts := keys.Conflicts(); len(conflicts) > 0 {
+		fmt.Println("Error: conflicting key bindings in config:")
+		for _, c := range conflicts {
+			fmt.Printf("  - %s\n", c)
+		}
+
+		os.Exit(1)
+	}
+
+	appearance := ui.DetectAppearance()
+	if _, err := ui.NewStyles(config, appearance); err != nil {
+		fmt.Printf("Error in styles: %v\n", err)
 
 		os.Exit(1)
 	}
 
-	monitorService := services.NewMonitorService(networkAdapter, loggerAdapter, repoAdapter, config)
 	statsService := services.NewStatsService(repoAdapter)
+	historyService := services.NewHistoryService(loggerAdapter)
 
 	appServices := ui.AppServices{
-		Monitor: monitorService,
-		Stats:   statsService,
-		Config:  config,
-		ConfigOnSave: func(c *domain.Config) error {
-			return configAdapter.Save(c)
-		},
+		Monitor:      monitorService,
+		Stats:        statsService,
+		History:      historyService,
+		Config:       config.Clone(),
+		ConfigOnSave: configAdapter.Save,
+		Context:      ctx,
 	}
 
-	model := ui.NewModel(appServices)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	model := ui.NewModel(appServices).WithAppearance(appearance)
+	// A signal cancels ctx, which ends the program too. Bubble Tea's own
+	// handler would race it and can block the shutdown for good.
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(ctx), tea.WithoutSignalHandler())
 
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error running program: %v\n", err)
+	final, err := p.Run()
+	signalled := ctx.Err() != nil
+
+	// Stop a check that is still running first, so it cannot disable an
+	// interface after it has been restored. This runs however the program
+	// ended, before anything exits.
+	cancel()
+	_ = monitorService.Restore(context.Background())
+
+	if err != nil {
+		if !signalled {
+			fmt.Printf("Error running program: %v\n", err)
+		}
 		os.Exit(1)
 	}
 
-	_ = monitorService.Restore()
+	if m, ok := final.(ui.Model); ok && m.ElevateRequested() {
+		if err := execWithSudo(config.SudoPath, args); err != nil {
+			fmt.Printf("Error restarting with sudo: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// splitObserveFlag removes --observe from the arguments and reports whether
+// it was given
+func splitObserveFlag(args []string) ([]string, bool) {
+	var rest []string
+	observe := false
+	for _, arg := range args {
+		if arg == "--observe" || arg == "-observe" {
+			observe = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, observe
+}
+
+// loadRecentEvents fills repo from the daily logs that cover the given
+// duration, so windows reaching back past midnight are complete
+func loadRecentEvents(ctx context.Context, history ports.HistoryPort, repo ports.EventRepository, duration time.Duration) error {
+	now := time.Now()
+	since := now.Add(-duration)
+	first := time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, since.Location())
+
+	var errs []error
+	for day := first; !day.After(now); day = day.AddDate(0, 0, 1) {
+		events, err := history.ReadEvents(day)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, e := range events {
+			repo.Add(ctx, e)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// execWithSudo replaces the process with this binary run through sudo,
+// without --observe, so it starts enforcing. sudo prompts for a password on
+// the terminal the TUI has just released.
+func execWithSudo(sudoPath string, args []string) error {
+	if sudoPath == "" {
+		sudoPath = "sudo"
+	}
+	sudo, err := exec.LookPath(sudoPath)
+	if err != nil {
+		return err
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	argv := append([]string{sudo, self}, args...)
+	return syscall.Exec(sudo, argv, os.Environ())
+}
+
+// newNetworkAdapter returns the configured backend. The ioctl backend falls
+// back to the shell when it cannot be used, and Linux systems without
+// ifconfig use ip.
+func newNetworkAdapter(config *domain.Config) ports.NetworkPort {
+	shell := network.NewShellNetworkAdapter().WithBinaries(config.IfconfigPath, config.SudoPath)
+
+	switch config.NetworkBackend {
+	case domain.BackendIP:
+		return network.NewIPNetworkAdapter().WithSudo(config.SudoPath)
+	case domain.BackendShell:
+		if _, err := exec.LookPath("ifconfig"); err != nil && config.IfconfigPath == "" && runtime.GOOS == "linux" {
+			return network.NewIPNetworkAdapter().WithSudo(config.SudoPath)
+		}
+		return shell
+	}
+
+	ioctl, err := network.NewIoctlNetworkAdapter()
+	if err != nil {
+		fmt.Printf("Warning: %v, using ifconfig instead\n", err)
+		return shell
+	}
+	return network.NewFallbackNetworkAdapter(ioctl, shell)
 }
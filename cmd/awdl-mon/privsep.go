@@ -0,0 +1,216 @@
+//go:build darwin || linux
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/helper"
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// helperCommand is the hidden subcommand the privileged helper runs as
+const helperCommand = "__helper"
+
+// invoker is the user who started awdl-mon through sudo
+type invoker struct {
+	uid    int
+	gid    int
+	groups []int
+	home   string
+}
+
+// sudoInvoker returns the user behind sudo when running as root through it
+func sudoInvoker() (invoker, bool) {
+	if os.Geteuid() != 0 {
+		return invoker{}, false
+	}
+	uid, err := strconv.Atoi(os.Getenv("SUDO_UID"))
+	if err != nil || uid == 0 {
+		return invoker{}, false
+	}
+
+	u, err := user.LookupId(strconv.Itoa(uid))
+	if err != nil {
+		return invoker{}, false
+	}
+	gid, err := strconv.Atoi(u.Gid)
+	if err != nil {
+		return invoker{}, false
+	}
+
+	inv := invoker{uid: uid, gid: gid, home: u.HomeDir}
+	groupIDs, _ := u.GroupIds()
+	for _, id := range groupIDs {
+		if n, err := strconv.Atoi(id); err == nil {
+			inv.groups = append(inv.groups, n)
+		}
+	}
+	if len(inv.groups) == 0 {
+		inv.groups = []int{gid}
+	}
+	return inv, true
+}
+
+// helperSudo is the sudo a helper that exited is started again with. The
+// config file belongs to the user, so its sudo_path is not trusted.
+const helperSudo = "/usr/bin/sudo"
+
+// Binaries the helper runs as root come from fixed system paths, never from
+// the user's config or PATH
+var (
+	ifconfigPaths = []string{"/sbin/ifconfig", "/usr/sbin/ifconfig"}
+	ipPaths       = []string{"/usr/sbin/ip", "/sbin/ip", "/usr/bin/ip", "/bin/ip"}
+)
+
+// newHelperStarter returns how to start this binary again as the privileged
+// helper, connected over a socketpair. As root the helper runs directly;
+// once privileges are dropped, a helper that exited is started again through
+// sudo -n, which only works while sudo needs no password. Every helper may
+// only change the interfaces guarded at startup, and exits when the
+// connection closes.
+func newHelperStarter(config *domain.Config) func() (net.Conn, error) {
+	args := []string{helperCommand,
+		"-backend", config.NetworkBackend,
+		"-allow", strings.Join(config.Interfaces, ","),
+		"-timeout", config.OperationTimeout.String()}
+
+	return func() (net.Conn, error) {
+		return startHelper(args, helperSudo)
+	}
+}
+
+// startHelper runs the helper with the given arguments. Its standard input
+// and output are its end of the socketpair; sudo passes both on, unlike other
+// descriptors.
+func startHelper(args []string, sudo string) (net.Conn, error) {
+	fds, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("creating socketpair: %w", err)
+	}
+	syscall.CloseOnExec(fds[0])
+	syscall.CloseOnExec(fds[1])
+	local := os.NewFile(uintptr(fds[0]), "helper")
+	remote := os.NewFile(uintptr(fds[1]), "monitor")
+	defer local.Close()
+	defer remote.Close()
+
+	self, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(self, args...)
+	if os.Geteuid() != 0 {
+		cmd = exec.Command(sudo, append([]string{"-n", self}, args...)...)
+	}
+	cmd.Stdin = remote
+	cmd.Stdout = remote
+	// A process group of its own keeps Ctrl+C from the terminal away, so the
+	// monitor can still restore the interfaces on the way out
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting helper: %w", err)
+	}
+	go cmd.Wait()
+
+	return net.FileConn(local)
+}
+
+// runHelper serves interface changes for the monitor on its standard input
+// and output
+func runHelper(args []string) error {
+	flags := flag.NewFlagSet(helperCommand, flag.ContinueOnError)
+	backend := flags.String("backend", "", "network backend")
+	allow := flags.String("allow", "", "comma-separated interfaces that may be changed")
+	timeout := flags.Duration("timeout", domain.DefaultConfig().OperationTimeout, "timeout of one change")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if os.Geteuid() != 0 {
+		return errors.New("the helper must run as root")
+	}
+
+	// Standard output carries the responses, so warnings go elsewhere
+	conn := struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+	os.Stdout = os.Stderr
+
+	var allowed []string
+	for _, name := range strings.Split(*allow, ",") {
+		if name != "" {
+			allowed = append(allowed, name)
+		}
+	}
+
+	server := helper.NewServer(helperNetworkAdapter(*backend), allowed).WithTimeout(*timeout)
+	return server.Serve(context.Background(), conn)
+}
+
+// helperNetworkAdapter returns the backend the helper changes interfaces
+// with. Like newNetworkAdapter, it uses ip on Linux systems without ifconfig.
+func helperNetworkAdapter(backend string) ports.NetworkPort {
+	ifconfig := systemBinary(ifconfigPaths)
+	shell := network.NewShellNetworkAdapter().WithBinaries(ifconfig, "")
+	ip := network.NewIPNetworkAdapter().WithBinary(systemBinary(ipPaths))
+
+	switch backend {
+	case domain.BackendIP:
+		return ip
+	case domain.BackendShell:
+		if _, err := os.Stat(ifconfig); err != nil && runtime.GOOS == "linux" {
+			return ip
+		}
+		return shell
+	}
+
+	ioctl, err := network.NewIoctlNetworkAdapter()
+	if err != nil {
+		return shell
+	}
+	return network.NewFallbackNetworkAdapter(ioctl, shell)
+}
+
+// systemBinary returns the first of paths that exists, or else the first one,
+// which then fails to run
+func systemBinary(paths []string) string {
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return paths[0]
+}
+
+// dropPrivileges switches the process to the invoking user for good
+func dropPrivileges(inv invoker) error {
+	if err := syscall.Setgroups(inv.groups); err != nil {
+		return fmt.Errorf("setgroups: %w", err)
+	}
+	if err := syscall.Setgid(inv.gid); err != nil {
+		return fmt.Errorf("setgid: %w", err)
+	}
+	if err := syscall.Setuid(inv.uid); err != nil {
+		return fmt.Errorf("setuid: %w", err)
+	}
+	if os.Geteuid() == 0 || syscall.Setuid(0) == nil {
+		return errors.New("still root after dropping privileges")
+	}
+	return os.Setenv("HOME", inv.home)
+}
@@ -0,0 +1,38 @@
+//go:build !darwin && !linux
+
+package main
+
+import (
+	"errors"
+	"net"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+const helperCommand = "__helper"
+
+var errNoPrivsep = errors.New("privilege separation is not supported on this system")
+
+// invoker is the user who started awdl-mon through sudo
+type invoker struct {
+	home string
+}
+
+// sudoInvoker never finds a sudo user here, so everything runs in one process
+func sudoInvoker() (invoker, bool) {
+	return invoker{}, false
+}
+
+func newHelperStarter(*domain.Config) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		return nil, errNoPrivsep
+	}
+}
+
+func runHelper([]string) error {
+	return errNoPrivsep
+}
+
+func dropPrivileges(invoker) error {
+	return errNoPrivsep
+}
@@ -1,9 +1,9 @@
 package configuration
 
 import (
+	"context"
 	"encoding/json"
 	"os"
-	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
@@ -16,29 +16,38 @@ func NewJSONConfigAdapter(path string) *JSONConfigAdapter {
 	return &JSONConfigAdapter{FilePath: path}
 }
 
-func (a *JSONConfigAdapter) Load() (*domain.Config, error) {
+func (a *JSONConfigAdapter) Load(ctx context.Context) (*domain.Config, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	file, err := os.Open(a.FilePath)
 	if os.IsNotExist(err) {
 		// Default Configuration
-		return &domain.Config{PollingInterval: 1 * time.Second}, nil
+		return domain.DefaultConfig(), nil
 	}
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var config domain.Config
+	// Fields missing from the file keep their default values
+	config := domain.DefaultConfig()
 	decoder := json.NewDecoder(file)
-	if err := decoder.Decode(&config); err != nil {
+	if err := decoder.Decode(config); err != nil {
 		return nil, err
 	}
 
 	config.Clamp()
 
-	return &config, nil
+	return config, nil
 }
 
-func (a *JSONConfigAdapter) Save(config *domain.Config) error {
+func (a *JSONConfigAdapter) Save(ctx context.Context, config *domain.Config) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	file, err := os.Create(a.FilePath)
 	if err != nil {
 		return err
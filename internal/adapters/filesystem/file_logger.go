@@ -1,17 +1,34 @@
 package filesystem
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
+// upSuffix introduces the UP duration appended to a logged message
+const upSuffix = " (up "
+
 type FileLoggerAdapter struct {
 	LogDir string
+
+	// mu guards tail, which remembers how many events the day's log held
+	// after the last write so the next cursor needs no rereading
+	mu   sync.Mutex
+	tail logTail
+}
+
+type logTail struct {
+	day    string
+	size   int64
+	events int
 }
 
 func NewFileLoggerAdapter(dir string) *FileLoggerAdapter {
@@ -19,24 +36,100 @@ func NewFileLoggerAdapter(dir string) *FileLoggerAdapter {
 	return &FileLoggerAdapter{LogDir: dir}
 }
 
-func (l *FileLoggerAdapter) Log(event domain.Event) error {
-	filename := event.Timestamp.Format("2006-01-02") + ".log"
-	path := filepath.Join(l.LogDir, filename)
+// Log appends an event to the log of its day and returns its position there
+func (l *FileLoggerAdapter) Log(ctx context.Context, event domain.Event) (domain.HistoryCursor, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.HistoryCursor{}, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	day := event.Timestamp.Format("2006-01-02")
+	path := filepath.Join(l.LogDir, day+".log")
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_RDWR, 0644)
 	if err != nil {
-		return err
+		return domain.HistoryCursor{}, err
 	}
 	defer file.Close()
 
+	info, err := file.Stat()
+	if err != nil {
+		return domain.HistoryCursor{}, err
+	}
+
+	// Another process, or a failed write, may have changed the file since
+	// the last event, so count its events again
+	if l.tail.day != day || l.tail.size != info.Size() {
+		events, err := l.ReadEvents(event.Timestamp)
+		if err != nil {
+			return domain.HistoryCursor{}, err
+		}
+		l.tail = logTail{day: day, size: info.Size(), events: len(events)}
+	}
+
+	message := event.Message
+	if event.UpDuration > 0 {
+		message += fmt.Sprintf("%s%v)", upSuffix, event.UpDuration)
+	}
+
 	line := fmt.Sprintf("[%s] %s: %s\n",
 		event.Timestamp.Format("15:04:05"),
 		event.Type,
-		event.Message,
+		message,
 	)
 
-	_, err = file.WriteString(line)
-	return err
+	// A torn last line would swallow this event
+	if size := info.Size(); size > 0 {
+		last := make([]byte, 1)
+		if _, err := file.ReadAt(last, size-1); err == nil && last[0] != '\n' {
+			line = "\n" + line
+		}
+	}
+
+	n, err := file.WriteString(line)
+	l.tail.size += int64(n)
+	if err != nil {
+		return domain.HistoryCursor{}, err
+	}
+
+	ts := event.Timestamp
+	cursor := domain.HistoryCursor{
+		Day:   time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location()),
+		Index: l.tail.events,
+	}
+	l.tail.events++
+
+	return cursor, nil
+}
+
+// Days lists the dates that have a log file, oldest first
+func (l *FileLoggerAdapter) Days() ([]time.Time, error) {
+	entries, err := os.ReadDir(l.LogDir)
+	if os.IsNotExist(err) {
+		return []time.Time{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var days []time.Time
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+
+		day, err := time.ParseInLocation("2006-01-02", strings.TrimSuffix(entry.Name(), ".log"), time.Local)
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	return days, nil
 }
 
 // ReadEvents reads log events for a specific date
@@ -84,14 +177,41 @@ func (l *FileLoggerAdapter) ReadEvents(date time.Time) ([]domain.Event, error) {
 			continue
 		}
 		eventType := domain.EventType(msgParts[0])
-		message := msgParts[1]
+		message, upDuration := splitUpDuration(msgParts[1])
+
+		event := domain.Event{
+			Timestamp:  fullTime,
+			Type:       eventType,
+			Message:    message,
+			UpDuration: upDuration,
+		}
+
+		// Check messages read "<interface> is <status>", optionally followed
+		// by ": <reason>"
+		if eventType == domain.EventCheck {
+			if name, status, ok := strings.Cut(message, " is "); ok {
+				status, _, _ = strings.Cut(status, ": ")
+				event.Interface = name
+				event.Status = domain.Status(status)
+			}
+		}
 
-		events = append(events, domain.Event{
-			Timestamp: fullTime,
-			Type:      eventType,
-			Message:   message,
-		})
+		events = append(events, event)
 	}
 
 	return events, nil
 }
+
+// splitUpDuration separates the UP duration written by Log from a message
+func splitUpDuration(message string) (string, time.Duration) {
+	i := strings.LastIndex(message, upSuffix)
+	if i == -1 || !strings.HasSuffix(message, ")") {
+		return message, 0
+	}
+
+	d, err := time.ParseDuration(message[i+len(upSuffix) : len(message)-1])
+	if err != nil {
+		return message, 0
+	}
+	return message[:i], d
+}
@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"context"
 	"os"
 	"testing"
 	"time"
@@ -33,7 +34,7 @@ func TestFileLoggerAdapter_ReadEvents(t *testing.T) {
 	}
 
 	for _, e := range events {
-		if err := adapter.Log(e); err != nil {
+		if _, err := adapter.Log(context.Background(), e); err != nil {
 			t.Fatalf("Failed to log event: %v", err)
 		}
 	}
@@ -64,3 +65,125 @@ func TestFileLoggerAdapter_ReadEvents(t *testing.T) {
 		}
 	}
 }
+
+func TestFileLoggerAdapter_Days(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "logger_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	adapter := NewFileLoggerAdapter(tmpDir)
+	newer := time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)
+	older := time.Date(2025, 2, 28, 10, 0, 0, 0, time.Local)
+
+	for _, ts := range []time.Time{newer, older} {
+		if _, err := adapter.Log(context.Background(), domain.Event{Timestamp: ts, Type: domain.EventDisable, Message: "event"}); err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(tmpDir+"/notes.txt", []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	days, err := adapter.Days()
+	if err != nil {
+		t.Fatalf("Failed to list days: %v", err)
+	}
+
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(days))
+	}
+	if days[0].Format("2006-01-02") != "2025-02-28" || days[1].Format("2006-01-02") != "2025-03-02" {
+		t.Errorf("Expected days in chronological order, got %v", days)
+	}
+}
+
+func TestFileLoggerAdapter_UpDurationRoundTrip(t *testing.T) {
+	adapter := NewFileLoggerAdapter(t.TempDir())
+	ts := time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)
+
+	logged := []domain.Event{
+		{Timestamp: ts, Type: domain.EventDisable, Message: "awdl0 detected UP. Disabling...", UpDuration: 1250 * time.Millisecond},
+		{Timestamp: ts, Type: domain.EventEnable, Message: "awdl0 enabled (manually)"},
+	}
+	for _, e := range logged {
+		if _, err := adapter.Log(context.Background(), e); err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+	}
+
+	events, err := adapter.ReadEvents(ts)
+	if err != nil {
+		t.Fatalf("Failed to read events: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(events))
+	}
+	for i, e := range events {
+		if e.Message != logged[i].Message || e.UpDuration != logged[i].UpDuration {
+			t.Errorf("Expected %q up %v, got %q up %v", logged[i].Message, logged[i].UpDuration, e.Message, e.UpDuration)
+		}
+	}
+}
+
+func TestFileLoggerAdapter_CheckEventsKeepStatus(t *testing.T) {
+	adapter := NewFileLoggerAdapter(t.TempDir())
+	ts := time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)
+
+	checks := []domain.Event{
+		{Timestamp: ts, Type: domain.EventCheck, Interface: "awdl0", Message: "awdl0 is UP", Status: domain.StatusUp},
+		{Timestamp: ts, Type: domain.EventCheck, Interface: "awdl0", Message: "awdl0 is Unknown: permission denied", Status: domain.StatusUnknown},
+	}
+	for _, check := range checks {
+		if _, err := adapter.Log(context.Background(), check); err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+	}
+
+	events, err := adapter.ReadEvents(ts)
+	if err != nil || len(events) != len(checks) {
+		t.Fatalf("Expected %d events, got %v, %v", len(checks), events, err)
+	}
+	for i, want := range checks {
+		if events[i].Interface != want.Interface || events[i].Status != want.Status {
+			t.Errorf("Expected %q %q, got %q %q", want.Interface, want.Status, events[i].Interface, events[i].Status)
+		}
+	}
+}
+
+func TestFileLoggerAdapter_Cursors(t *testing.T) {
+	dir := t.TempDir()
+	adapter := NewFileLoggerAdapter(dir)
+	ts := time.Date(2025, 3, 2, 10, 0, 0, 0, time.Local)
+	event := domain.Event{Timestamp: ts, Type: domain.EventCheck, Message: "awdl0 is DOWN"}
+
+	for want := 0; want < 2; want++ {
+		cursor, err := adapter.Log(context.Background(), event)
+		if err != nil {
+			t.Fatalf("Failed to log event: %v", err)
+		}
+		if cursor.Index != want || cursor.Day.Format("2006-01-02") != "2025-03-02" {
+			t.Errorf("Expected 2025-03-02 #%d, got %s #%d", want, cursor.Day.Format("2006-01-02"), cursor.Index)
+		}
+	}
+
+	// Another process logs an event, and a torn line is not an event
+	file, err := os.OpenFile(dir+"/2025-03-02.log", os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("[10:00:01] SnoozeStart: awdl0 allowed\n[10:00:0")
+	file.Close()
+
+	cursor, err := adapter.Log(context.Background(), event)
+	if err != nil {
+		t.Fatalf("Failed to log event: %v", err)
+	}
+	events, _ := adapter.ReadEvents(ts)
+	if cursor.Index != 3 || len(events) != 4 {
+		t.Errorf("Expected the cursor to count the other process's event, got #%d of %d", cursor.Index, len(events))
+	}
+}
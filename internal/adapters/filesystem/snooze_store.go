@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// JSONSnoozeStore keeps active snoozes in a JSON file, so an `awdl-mon allow`
+// run is picked up by a running monitor
+type JSONSnoozeStore struct {
+	FilePath string
+}
+
+func NewJSONSnoozeStore(path string) *JSONSnoozeStore {
+	return &JSONSnoozeStore{FilePath: path}
+}
+
+func (s *JSONSnoozeStore) Load() ([]domain.Snooze, error) {
+	content, err := os.ReadFile(s.FilePath)
+	if os.IsNotExist(err) {
+		return []domain.Snooze{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snoozes []domain.Snooze
+	if err := json.Unmarshal(content, &snoozes); err != nil {
+		return nil, err
+	}
+
+	return snoozes, nil
+}
+
+// Save replaces the file atomically, so a concurrent Load never sees a
+// partial write
+func (s *JSONSnoozeStore) Save(snoozes []domain.Snooze) error {
+	content, err := json.MarshalIndent(snoozes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.FilePath), ".snooze-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.FilePath)
+}
@@ -0,0 +1,42 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestJSONSnoozeStore_RoundTrip(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "snooze_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(tmpDir)
+
+	store := NewJSONSnoozeStore(filepath.Join(tmpDir, "snooze.json"))
+
+	snoozes, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load missing file: %v", err)
+	}
+	if len(snoozes) != 0 {
+		t.Errorf("Expected no snoozes, got %v", snoozes)
+	}
+
+	until := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	if err := store.Save([]domain.Snooze{{Interface: "awdl0", Until: until}}); err != nil {
+		t.Fatalf("Failed to save: %v", err)
+	}
+
+	snoozes, err = store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load: %v", err)
+	}
+	if len(snoozes) != 1 || snoozes[0].Interface != "awdl0" || !snoozes[0].Until.Equal(until) {
+		t.Errorf("Expected the saved snooze back, got %v", snoozes)
+	}
+}
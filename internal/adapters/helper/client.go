@@ -0,0 +1,210 @@
+package helper
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// NetworkAdapter is the unprivileged side. It reads interfaces itself and
+// sends every change to the helper. When the helper exits, the next change
+// starts a new one, if the adapter knows how, and hands it the allow list.
+type NetworkAdapter struct {
+	reader  ports.NetworkPort
+	restart func() (net.Conn, error)
+
+	mu sync.Mutex
+	// conn is nil while no helper is running
+	conn     net.Conn
+	response *bufio.Reader
+	nextID   uint64
+	allowed  []string
+	closed   bool
+}
+
+func NewNetworkAdapter(reader ports.NetworkPort, conn net.Conn) *NetworkAdapter {
+	return &NetworkAdapter{
+		reader:   reader,
+		conn:     conn,
+		response: bufio.NewReader(conn),
+	}
+}
+
+// WithRestart starts a new helper with restart whenever the running one is
+// gone
+func (a *NetworkAdapter) WithRestart(restart func() (net.Conn, error)) *NetworkAdapter {
+	a.restart = restart
+	return a
+}
+
+func (a *NetworkAdapter) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	return a.reader.CheckInterface(ctx, name)
+}
+
+func (a *NetworkAdapter) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	return a.reader.DescribeInterface(ctx, name)
+}
+
+func (a *NetworkAdapter) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	return a.reader.ListInterfaces(ctx)
+}
+
+func (a *NetworkAdapter) DisableInterface(ctx context.Context, name string) error {
+	return a.request(ctx, Request{Op: OpDown, Interface: name})
+}
+
+func (a *NetworkAdapter) EnableInterface(ctx context.Context, name string) error {
+	return a.request(ctx, Request{Op: OpUp, Interface: name})
+}
+
+// AllowInterfaces narrows the helper's allow list to names, which must be
+// among those it was started with. The list is handed to every helper started
+// later, too.
+func (a *NetworkAdapter) AllowInterfaces(ctx context.Context, names []string) error {
+	names = append([]string{}, names...)
+	if err := a.request(ctx, Request{Op: OpAllow, Interfaces: names}); err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.allowed = names
+	a.mu.Unlock()
+	return nil
+}
+
+// Close ends the connection, which makes the helper exit. No helper is
+// started afterwards.
+func (a *NetworkAdapter) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	if a.conn == nil {
+		return nil
+	}
+	err := a.conn.Close()
+	a.conn = nil
+	return err
+}
+
+// request sends one request and waits for its response until ctx is done.
+// A helper that is gone is started again, and a request it took down with it
+// is sent once more; bringing an interface up or down twice does no harm.
+func (a *NetworkAdapter) request(ctx context.Context, req Request) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	restarted := false
+	if a.conn == nil {
+		if err := a.reconnect(ctx); err != nil {
+			return err
+		}
+		restarted = true
+	}
+
+	err := a.exchange(ctx, req)
+	if errors.Is(err, domain.ErrHelperUnavailable) && !restarted && a.restart != nil {
+		if err := a.reconnect(ctx); err != nil {
+			return err
+		}
+		err = a.exchange(ctx, req)
+	}
+	return err
+}
+
+// reconnect starts a new helper and hands it the allow list
+func (a *NetworkAdapter) reconnect(ctx context.Context) error {
+	if a.closed || a.restart == nil {
+		return fmt.Errorf("helper: %w", domain.ErrHelperUnavailable)
+	}
+
+	conn, err := a.restart()
+	if err != nil {
+		return fmt.Errorf("helper: restarting: %v: %w", err, domain.ErrHelperUnavailable)
+	}
+	a.conn = conn
+	a.response = bufio.NewReader(conn)
+
+	if err := a.exchange(ctx, Request{Op: OpAllow, Interfaces: a.allowed}); err != nil {
+		return fmt.Errorf("helper: restarted: %w", err)
+	}
+	return nil
+}
+
+// exchange sends one request over the current connection and reads its
+// response. A connection the helper has closed is dropped.
+func (a *NetworkAdapter) exchange(ctx context.Context, req Request) error {
+	deadline, _ := ctx.Deadline()
+	if err := a.conn.SetDeadline(deadline); err != nil {
+		return a.failed(ctx, err)
+	}
+	// Cancelling interrupts a pending read or write. The next request must
+	// not start before that has happened, or it would inherit the deadline.
+	conn := a.conn
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now())
+		close(interrupted)
+	})
+	defer func() {
+		if !stop() {
+			<-interrupted
+		}
+	}()
+
+	a.nextID++
+	req.ID = a.nextID
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return a.failed(ctx, err)
+	}
+
+	for {
+		line, err := a.response.ReadBytes('\n')
+		if err != nil {
+			return a.failed(ctx, err)
+		}
+
+		// A fragment left over from an abandoned read does not decode
+		var resp Response
+		if err := json.Unmarshal(line, &resp); err == nil && resp.ID == req.ID {
+			return resp.Err()
+		}
+	}
+}
+
+// failed reports a broken exchange, preferring the context's reason. When
+// the helper is gone, the connection is dropped.
+func (a *NetworkAdapter) failed(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return context.DeadlineExceeded
+	}
+	if gone(err) {
+		a.conn.Close()
+		a.conn = nil
+		return fmt.Errorf("helper: exited: %w", domain.ErrHelperUnavailable)
+	}
+	return fmt.Errorf("helper: %v: %w", err, domain.ErrCommandFailed)
+}
+
+// gone reports whether err means the other end of the connection is closed
+func gone(err error) bool {
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,260 @@
+package helper_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/helper"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// fakeHelper stands in for the privileged process. It records requests and
+// answers each with the next scripted reply.
+type fakeHelper struct {
+	server   net.Conn
+	requests chan helper.Request
+	replies  chan func(helper.Request) []helper.Response
+}
+
+func startFakeHelper(t *testing.T) (*fakeHelper, *helper.NetworkAdapter) {
+	t.Helper()
+	f, client := newFakeHelper(t)
+	adapter := helper.NewNetworkAdapter(&stubNetwork{status: domain.StatusUp}, client)
+	t.Cleanup(func() { adapter.Close() })
+	return f, adapter
+}
+
+// newFakeHelper serves on one end of a socket pair and returns the other
+func newFakeHelper(t *testing.T) (*fakeHelper, net.Conn) {
+	t.Helper()
+	client, server := socketPair(t)
+	f := &fakeHelper{
+		server:   server,
+		requests: make(chan helper.Request, 10),
+		replies:  make(chan func(helper.Request) []helper.Response, 10),
+	}
+
+	go func() {
+		scanner := bufio.NewScanner(server)
+		encoder := json.NewEncoder(server)
+		for scanner.Scan() {
+			var req helper.Request
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+				t.Errorf("Expected a JSON request, got %q", scanner.Text())
+				return
+			}
+			f.requests <- req
+			for _, resp := range (<-f.replies)(req) {
+				if encoder.Encode(resp) != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	t.Cleanup(func() { server.Close() })
+	return f, client
+}
+
+// socketPair connects over a Unix socket, which buffers like the socketpair
+// the monitor uses, unlike net.Pipe
+func socketPair(t *testing.T) (net.Conn, net.Conn) {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "helper")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	listener, err := net.Listen("unix", filepath.Join(dir, "sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	client, err := net.Dial("unix", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, server
+}
+
+// exit closes the helper's end, as if it had died
+func (f *fakeHelper) exit() {
+	f.server.Close()
+}
+
+// reply answers with the given response, under the request's ID
+func (f *fakeHelper) reply(resp helper.Response) {
+	f.replies <- func(req helper.Request) []helper.Response {
+		resp.ID = req.ID
+		return []helper.Response{resp}
+	}
+}
+
+func TestNetworkAdapter_Requests(t *testing.T) {
+	f, adapter := startFakeHelper(t)
+	f.reply(helper.Response{OK: true})
+	f.reply(helper.Response{OK: true})
+
+	if err := adapter.DisableInterface(context.Background(), "awdl0"); err != nil {
+		t.Fatalf("Expected disable to succeed, got %v", err)
+	}
+	if err := adapter.EnableInterface(context.Background(), "llw0"); err != nil {
+		t.Fatalf("Expected enable to succeed, got %v", err)
+	}
+
+	first, second := <-f.requests, <-f.requests
+	if first.Op != helper.OpDown || first.Interface != "awdl0" {
+		t.Errorf("Expected down awdl0, got %+v", first)
+	}
+	if second.Op != helper.OpUp || second.Interface != "llw0" {
+		t.Errorf("Expected up llw0, got %+v", second)
+	}
+	if first.ID == second.ID {
+		t.Errorf("Expected distinct request IDs, got %d twice", first.ID)
+	}
+}
+
+func TestNetworkAdapter_ReadsLocally(t *testing.T) {
+	f, adapter := startFakeHelper(t)
+
+	status, err := adapter.CheckInterface(context.Background(), "awdl0")
+	if err != nil || status != domain.StatusUp {
+		t.Errorf("Expected UP from the local reader, got %s, %v", status, err)
+	}
+	if len(f.requests) != 0 {
+		t.Errorf("Expected checks not to reach the helper, got %d requests", len(f.requests))
+	}
+}
+
+func TestNetworkAdapter_Errors(t *testing.T) {
+	tests := []struct {
+		kind string
+		want error
+	}{
+		{helper.KindRejected, helper.ErrRejected},
+		{helper.KindNotFound, domain.ErrInterfaceNotFound},
+		{helper.KindPermission, domain.ErrPermissionDenied},
+		{helper.KindFailed, domain.ErrCommandFailed},
+		{"unheard-of", domain.ErrCommandFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			f, adapter := startFakeHelper(t)
+			f.reply(helper.Response{Error: "it went wrong", Kind: tt.kind})
+
+			err := adapter.DisableInterface(context.Background(), "awdl0")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestNetworkAdapter_Timeout(t *testing.T) {
+	f, adapter := startFakeHelper(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := adapter.DisableInterface(ctx, "awdl0")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to end the wait, got %v", err)
+	}
+
+	// The late answer to the abandoned request must not be taken for the
+	// answer to the next one
+	late := <-f.requests
+	f.replies <- func(helper.Request) []helper.Response {
+		return []helper.Response{{ID: late.ID, Error: "late", Kind: helper.KindFailed}}
+	}
+	f.replies <- func(req helper.Request) []helper.Response {
+		return []helper.Response{{ID: req.ID, OK: true}}
+	}
+
+	if err := adapter.EnableInterface(context.Background(), "awdl0"); err != nil {
+		t.Errorf("Expected the stale response to be skipped, got %v", err)
+	}
+}
+
+func TestNetworkAdapter_Cancelled(t *testing.T) {
+	f, adapter := startFakeHelper(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-f.requests
+		cancel()
+	}()
+
+	err := adapter.DisableInterface(ctx, "awdl0")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancellation to end the wait, got %v", err)
+	}
+}
+
+func TestNetworkAdapter_HelperGone(t *testing.T) {
+	client, server := socketPair(t)
+	server.Close()
+	adapter := helper.NewNetworkAdapter(&stubNetwork{}, client)
+	defer adapter.Close()
+
+	for i := 0; i < 2; i++ {
+		err := adapter.DisableInterface(context.Background(), "awdl0")
+		if !errors.Is(err, domain.ErrHelperUnavailable) {
+			t.Errorf("Expected the helper to be unavailable, got %v", err)
+		}
+	}
+}
+
+func TestNetworkAdapter_Restart(t *testing.T) {
+	f, adapter := startFakeHelper(t)
+	f.reply(helper.Response{OK: true})
+	if err := adapter.AllowInterfaces(context.Background(), []string{"awdl0", "llw0"}); err != nil {
+		t.Fatalf("Expected the allow list to be accepted, got %v", err)
+	}
+	<-f.requests
+
+	var restarted *fakeHelper
+	adapter.WithRestart(func() (net.Conn, error) {
+		var conn net.Conn
+		restarted, conn = newFakeHelper(t)
+		restarted.reply(helper.Response{OK: true})
+		restarted.reply(helper.Response{OK: true})
+		return conn, nil
+	})
+
+	// The helper dies; the next change starts a new one
+	f.exit()
+	if err := adapter.DisableInterface(context.Background(), "awdl0"); err != nil {
+		t.Fatalf("Expected the change to go through a new helper, got %v", err)
+	}
+	if restarted == nil {
+		t.Fatal("Expected a new helper to be started")
+	}
+	allow, down := <-restarted.requests, <-restarted.requests
+	if allow.Op != helper.OpAllow || strings.Join(allow.Interfaces, ",") != "awdl0,llw0" {
+		t.Errorf("Expected the allow list first, got %+v", allow)
+	}
+	if down.Op != helper.OpDown || down.Interface != "awdl0" {
+		t.Errorf("Expected down awdl0, got %+v", down)
+	}
+
+	adapter.WithRestart(func() (net.Conn, error) { return nil, errors.New("sudo: a password is required") })
+	restarted.exit()
+	if err := adapter.EnableInterface(context.Background(), "awdl0"); !errors.Is(err, domain.ErrHelperUnavailable) {
+		t.Errorf("Expected the helper to be unavailable when it cannot be restarted, got %v", err)
+	}
+}
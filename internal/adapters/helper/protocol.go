@@ -0,0 +1,96 @@
+// Package helper moves interface changes into a small privileged process, so
+// the rest of the monitor can run as the invoking user. The monitor sends one
+// JSON request per line and the helper answers each with one JSON response:
+//
+//	{"id":1,"op":"allow","interfaces":["awdl0","llw0"]}
+//	{"id":1,"ok":true}
+//	{"id":2,"op":"down","interface":"awdl0"}
+//	{"id":2,"ok":true}
+//	{"id":3,"error":"\"en0\" is not an allowed interface","kind":"rejected"}
+//
+// The helper only brings the interfaces on its allow list up or down, and
+// everything else is rejected without running anything. The list is fixed
+// when the helper starts, and holds peer-to-peer interfaces only. The monitor
+// narrows it to the guarded interfaces whenever they change, but can never
+// add an interface the helper was not started with.
+package helper
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+const (
+	OpUp   = "up"
+	OpDown = "down"
+	// OpAllow narrows the allow list
+	OpAllow = "allow"
+)
+
+// Kinds of failed responses
+const (
+	// KindRejected means the request was invalid and nothing was run
+	KindRejected   = "rejected"
+	KindNotFound   = "not_found"
+	KindPermission = "permission"
+	KindFailed     = "failed"
+)
+
+// MaxRequestSize bounds a request line. A longer line ends the connection.
+const MaxRequestSize = 1024
+
+// MaxAllowed bounds the allow list
+const MaxAllowed = 32
+
+// ErrRejected matches the errors of requests the helper refused to run
+var ErrRejected = errors.New("rejected by helper")
+
+// Request asks the helper to bring an interface up or down, or to narrow
+// its allow list to Interfaces. ID is echoed in the response.
+type Request struct {
+	ID         uint64   `json:"id"`
+	Op         string   `json:"op"`
+	Interface  string   `json:"interface,omitempty"`
+	Interfaces []string `json:"interfaces,omitempty"`
+}
+
+// Response answers the request with the same ID
+type Response struct {
+	ID    uint64 `json:"id"`
+	OK    bool   `json:"ok,omitempty"`
+	Error string `json:"error,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+}
+
+// failure describes an error in a response, keeping what kind it was
+func failure(id uint64, err error) Response {
+	kind := KindFailed
+	switch {
+	case errors.Is(err, domain.ErrInterfaceNotFound):
+		kind = KindNotFound
+	case errors.Is(err, domain.ErrPermissionDenied):
+		kind = KindPermission
+	}
+	return Response{ID: id, Error: err.Error(), Kind: kind}
+}
+
+// Err turns a failed response back into an error that matches ErrRejected
+// or the domain errors
+func (r Response) Err() error {
+	if r.OK {
+		return nil
+	}
+
+	kind := domain.ErrCommandFailed
+	switch r.Kind {
+	case KindRejected:
+		kind = ErrRejected
+	case KindNotFound:
+		kind = domain.ErrInterfaceNotFound
+	case KindPermission:
+		kind = domain.ErrPermissionDenied
+	}
+	return fmt.Errorf("helper: %s: %w", r.Error, kind)
+}
@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// Server is the privileged side. It brings the allowed interfaces up or down
+// and refuses everything else.
+type Server struct {
+	network ports.NetworkPort
+	// limit is the allow list the server was started with. The monitor may
+	// narrow the allow list, but never beyond it.
+	limit   map[string]bool
+	allowed map[string]bool
+	timeout time.Duration
+}
+
+// NewServer starts with the given allow list, dropping the names the policy
+// refuses. The list cannot be widened later.
+func NewServer(network ports.NetworkPort, allowed []string) *Server {
+	s := &Server{
+		network: network,
+		limit:   make(map[string]bool),
+		allowed: make(map[string]bool),
+		timeout: domain.DefaultConfig().OperationTimeout,
+	}
+	for _, name := range allowed {
+		if Policy(name) == nil {
+			s.limit[name] = true
+			s.allowed[name] = true
+		}
+	}
+	return s
+}
+
+// P2PPrefixes are the names of the peer-to-peer interfaces the helper may
+// change: AWDL and low latency WLAN on macOS, Wi-Fi Direct on Linux
+var P2PPrefixes = []string{"awdl", "llw", "p2p-"}
+
+// Policy decides which interfaces may be put on the allow list: only valid
+// names of peer-to-peer interfaces, so the helper can never take down a
+// regular link
+func Policy(name string) error {
+	if !domain.ValidInterfaceName(name) {
+		return fmt.Errorf("%q is not a valid interface name", name)
+	}
+	for _, prefix := range P2PPrefixes {
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a peer-to-peer interface", name)
+}
+
+// WithTimeout bounds how long a single interface change may run
+func (s *Server) WithTimeout(d time.Duration) *Server {
+	if d > 0 {
+		s.timeout = d
+	}
+	return s
+}
+
+// Serve answers requests one at a time until conn is closed. It returns nil
+// at EOF and an error when a request line is too long to read.
+func (s *Server) Serve(ctx context.Context, conn io.ReadWriter) error {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, MaxRequestSize), MaxRequestSize)
+	encoder := json.NewEncoder(conn)
+
+	for scanner.Scan() {
+		if err := encoder.Encode(s.handle(ctx, scanner.Bytes())); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *Server) handle(ctx context.Context, line []byte) Response {
+	req, err := s.parse(line)
+	if err != nil {
+		return Response{ID: req.ID, Error: err.Error(), Kind: KindRejected}
+	}
+
+	if req.Op == OpAllow {
+		s.allowed = make(map[string]bool)
+		for _, name := range req.Interfaces {
+			s.allowed[name] = true
+		}
+		return Response{ID: req.ID, OK: true}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	if req.Op == OpDown {
+		err = s.network.DisableInterface(ctx, req.Interface)
+	} else {
+		err = s.network.EnableInterface(ctx, req.Interface)
+	}
+	if err != nil {
+		return failure(req.ID, err)
+	}
+	return Response{ID: req.ID, OK: true}
+}
+
+// parse decodes a request strictly and checks it against the allow list, or
+// a new allow list against the policy
+func (s *Server) parse(line []byte) (Request, error) {
+	var req Request
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return req, fmt.Errorf("malformed request: %v", err)
+	}
+	if decoder.More() {
+		return req, errors.New("trailing data after request")
+	}
+
+	switch req.Op {
+	case OpAllow:
+		if req.Interface != "" {
+			return req, errors.New("allow takes interfaces, not interface")
+		}
+		if len(req.Interfaces) > MaxAllowed {
+			return req, fmt.Errorf("at most %d interfaces may be allowed", MaxAllowed)
+		}
+		for _, name := range req.Interfaces {
+			if err := Policy(name); err != nil {
+				return req, err
+			}
+			if !s.limit[name] {
+				return req, fmt.Errorf("%q is not among the interfaces the helper was started with", name)
+			}
+		}
+		return req, nil
+	case OpUp, OpDown:
+		if req.Interfaces != nil {
+			return req, fmt.Errorf("%s takes interface, not interfaces", req.Op)
+		}
+	default:
+		return req, fmt.Errorf("unknown operation %q", req.Op)
+	}
+	if !s.allowed[req.Interface] {
+		return req, fmt.Errorf("%q is not an allowed interface", req.Interface)
+	}
+	return req, nil
+}
@@ -0,0 +1,212 @@
+package helper_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/helper"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// stubNetwork records interface changes and fails them with err
+type stubNetwork struct {
+	status   domain.Status
+	err      error
+	disabled []string
+	enabled  []string
+}
+
+func (s *stubNetwork) CheckInterface(context.Context, string) (domain.Status, error) {
+	return s.status, s.err
+}
+
+func (s *stubNetwork) DescribeInterface(_ context.Context, name string) (domain.Interface, error) {
+	return domain.Interface{Name: name, Status: s.status}, s.err
+}
+
+func (s *stubNetwork) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	return nil, s.err
+}
+
+func (s *stubNetwork) DisableInterface(_ context.Context, name string) error {
+	s.disabled = append(s.disabled, name)
+	return s.err
+}
+
+func (s *stubNetwork) EnableInterface(_ context.Context, name string) error {
+	s.enabled = append(s.enabled, name)
+	return s.err
+}
+
+// serve starts a server on one end of a pipe and returns the other end
+func serve(t *testing.T, network *stubNetwork) (net.Conn, <-chan error) {
+	t.Helper()
+	client, server := net.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- helper.NewServer(network, []string{"awdl0", "llw0"}).Serve(context.Background(), server)
+		server.Close()
+	}()
+	t.Cleanup(func() { client.Close() })
+	return client, done
+}
+
+func TestServer_Rejects(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"Unknown operation", `{"id":1,"op":"destroy","interface":"awdl0"}`},
+		{"Missing operation", `{"id":1,"interface":"awdl0"}`},
+		{"Interface not allowed", `{"id":1,"op":"down","interface":"en0"}`},
+		{"Invalid interface name", `{"id":1,"op":"down","interface":"awdl0; reboot"}`},
+		{"Option as interface", `{"id":1,"op":"down","interface":"-a"}`},
+		{"Unknown field", `{"id":1,"op":"down","interface":"awdl0","command":"rm"}`},
+		{"Malformed JSON", `{"id":1,"op":"down"`},
+		{"Trailing data", `{"id":1,"op":"down","interface":"awdl0"} {"op":"up"}`},
+		{"Not an object", `["down","awdl0"]`},
+		{"Allow with one interface", `{"id":1,"op":"allow","interface":"en0"}`},
+		{"Down with a list", `{"id":1,"op":"down","interface":"awdl0","interfaces":["en0"]}`},
+		{"Allow invalid name", `{"id":1,"op":"allow","interfaces":["awdl0","-a"]}`},
+		{"Allow a regular link", `{"id":1,"op":"allow","interfaces":["awdl0","en0"]}`},
+		{"Allow beyond the start list", `{"id":1,"op":"allow","interfaces":["awdl1"]}`},
+		{"Allow too many", `{"id":1,"op":"allow","interfaces":["` + strings.Repeat(`x","`, helper.MaxAllowed) + `y"]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			network := &stubNetwork{}
+			conn, _ := serve(t, network)
+
+			if _, err := conn.Write([]byte(tt.line + "\n")); err != nil {
+				t.Fatal(err)
+			}
+			var resp helper.Response
+			if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+				t.Fatalf("Expected a response, got %v", err)
+			}
+
+			if resp.OK || resp.Kind != helper.KindRejected {
+				t.Errorf("Expected a rejection, got %+v", resp)
+			}
+			if !errors.Is(resp.Err(), helper.ErrRejected) {
+				t.Errorf("Expected ErrRejected, got %v", resp.Err())
+			}
+			if len(network.disabled)+len(network.enabled) > 0 {
+				t.Errorf("Expected nothing to run, got disabled %v, enabled %v", network.disabled, network.enabled)
+			}
+		})
+	}
+}
+
+func TestServer_AllowList(t *testing.T) {
+	network := &stubNetwork{}
+	conn, _ := serve(t, network)
+	adapter := helper.NewNetworkAdapter(&stubNetwork{}, conn)
+	ctx := context.Background()
+
+	if err := adapter.AllowInterfaces(ctx, []string{"llw0"}); err != nil {
+		t.Fatalf("Expected the allow list to be narrowed, got %v", err)
+	}
+	if err := adapter.DisableInterface(ctx, "llw0"); err != nil {
+		t.Errorf("Expected llw0 to be allowed, got %v", err)
+	}
+	if err := adapter.DisableInterface(ctx, "awdl0"); !errors.Is(err, helper.ErrRejected) {
+		t.Errorf("Expected awdl0 to be off the list, got %v", err)
+	}
+
+	// The list may grow back to what the helper was started with, no further
+	if err := adapter.AllowInterfaces(ctx, []string{"awdl0", "llw0"}); err != nil {
+		t.Errorf("Expected the start list to be allowed again, got %v", err)
+	}
+	if err := adapter.AllowInterfaces(ctx, []string{"awdl0", "p2p-wlan0-0"}); !errors.Is(err, helper.ErrRejected) {
+		t.Errorf("Expected an interface beyond the start list to be refused, got %v", err)
+	}
+	if err := adapter.EnableInterface(ctx, "llw0"); err != nil {
+		t.Errorf("Expected a refused list to leave the previous one in place, got %v", err)
+	}
+}
+
+func TestServer_StartListFollowsPolicy(t *testing.T) {
+	client, server := net.Pipe()
+	go helper.NewServer(&stubNetwork{}, []string{"awdl0", "en0", "lo"}).Serve(context.Background(), server)
+	defer client.Close()
+	adapter := helper.NewNetworkAdapter(&stubNetwork{}, client)
+
+	if err := adapter.DisableInterface(context.Background(), "awdl0"); err != nil {
+		t.Errorf("Expected awdl0 to be allowed, got %v", err)
+	}
+	for _, name := range []string{"en0", "lo"} {
+		if err := adapter.DisableInterface(context.Background(), name); !errors.Is(err, helper.ErrRejected) {
+			t.Errorf("Expected %s to be dropped from the start list, got %v", name, err)
+		}
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	for _, name := range []string{"awdl0", "llw0", "p2p-dev-wlan0", "p2p-wlan0-0"} {
+		if err := helper.Policy(name); err != nil {
+			t.Errorf("Expected %s to be allowed, got %v", name, err)
+		}
+	}
+	for _, name := range []string{"en0", "utun0", "bridge0", "lo", "eth0", "awdl", "-a", "awdl0; reboot"} {
+		if err := helper.Policy(name); err == nil {
+			t.Errorf("Expected %s to be refused", name)
+		}
+	}
+}
+
+func TestServer_OverlongRequest(t *testing.T) {
+	network := &stubNetwork{}
+	conn, done := serve(t, network)
+
+	line := `{"id":1,"op":"down","interface":"awdl0","pad":"` + strings.Repeat("x", helper.MaxRequestSize) + `"}` + "\n"
+	go conn.Write([]byte(line))
+
+	if err := <-done; !errors.Is(err, bufio.ErrTooLong) {
+		t.Errorf("Expected the connection to end with ErrTooLong, got %v", err)
+	}
+	if len(network.disabled) > 0 {
+		t.Errorf("Expected nothing to run, got %v", network.disabled)
+	}
+}
+
+func TestServer_EndsAtEOF(t *testing.T) {
+	conn, done := serve(t, &stubNetwork{})
+	conn.Close()
+
+	if err := <-done; err != nil {
+		t.Errorf("Expected a clean end, got %v", err)
+	}
+}
+
+func TestServer_WithClient(t *testing.T) {
+	network := &stubNetwork{}
+	conn, _ := serve(t, network)
+	adapter := helper.NewNetworkAdapter(&stubNetwork{}, conn)
+	ctx := context.Background()
+
+	if err := adapter.DisableInterface(ctx, "awdl0"); err != nil {
+		t.Fatalf("Expected disable to succeed, got %v", err)
+	}
+	if err := adapter.EnableInterface(ctx, "llw0"); err != nil {
+		t.Fatalf("Expected enable to succeed, got %v", err)
+	}
+	if err := adapter.DisableInterface(ctx, "en0"); !errors.Is(err, helper.ErrRejected) {
+		t.Errorf("Expected en0 to be rejected, got %v", err)
+	}
+
+	network.err = &domain.CommandError{Command: "ifconfig awdl0 down", ExitCode: 1, Kind: domain.ErrPermissionDenied}
+	if err := adapter.DisableInterface(ctx, "awdl0"); !errors.Is(err, domain.ErrPermissionDenied) {
+		t.Errorf("Expected the helper's permission error, got %v", err)
+	}
+
+	if len(network.disabled) != 2 || len(network.enabled) != 1 {
+		t.Errorf("Expected two disables and one enable, got %v and %v", network.disabled, network.enabled)
+	}
+}
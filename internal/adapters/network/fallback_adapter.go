@@ -0,0 +1,66 @@
+package network
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// FallbackNetworkAdapter tries the primary adapter first and repeats an
+// operation on the fallback when it fails. A missing interface is an answer,
+// not a failure, so it is returned as is, and nothing is repeated once the
+// context is done.
+type FallbackNetworkAdapter struct {
+	primary  ports.NetworkPort
+	fallback ports.NetworkPort
+}
+
+func NewFallbackNetworkAdapter(primary, fallback ports.NetworkPort) *FallbackNetworkAdapter {
+	return &FallbackNetworkAdapter{primary: primary, fallback: fallback}
+}
+
+func (a *FallbackNetworkAdapter) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	status, err := a.primary.CheckInterface(ctx, name)
+	if shouldFallBack(ctx, err) {
+		return a.fallback.CheckInterface(ctx, name)
+	}
+	return status, err
+}
+
+func (a *FallbackNetworkAdapter) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	iface, err := a.primary.DescribeInterface(ctx, name)
+	if shouldFallBack(ctx, err) {
+		return a.fallback.DescribeInterface(ctx, name)
+	}
+	return iface, err
+}
+
+func (a *FallbackNetworkAdapter) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	ifaces, err := a.primary.ListInterfaces(ctx)
+	if shouldFallBack(ctx, err) {
+		return a.fallback.ListInterfaces(ctx)
+	}
+	return ifaces, err
+}
+
+func (a *FallbackNetworkAdapter) DisableInterface(ctx context.Context, name string) error {
+	err := a.primary.DisableInterface(ctx, name)
+	if shouldFallBack(ctx, err) {
+		return a.fallback.DisableInterface(ctx, name)
+	}
+	return err
+}
+
+func (a *FallbackNetworkAdapter) EnableInterface(ctx context.Context, name string) error {
+	err := a.primary.EnableInterface(ctx, name)
+	if shouldFallBack(ctx, err) {
+		return a.fallback.EnableInterface(ctx, name)
+	}
+	return err
+}
+
+func shouldFallBack(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && !errors.Is(err, domain.ErrInterfaceNotFound)
+}
@@ -0,0 +1,93 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// stubNetwork answers every check with the same status and error
+type stubNetwork struct {
+	status   domain.Status
+	err      error
+	checks   int
+	disables int
+}
+
+func (s *stubNetwork) CheckInterface(_ context.Context, name string) (domain.Status, error) {
+	s.checks++
+	return s.status, s.err
+}
+
+func (s *stubNetwork) DescribeInterface(_ context.Context, name string) (domain.Interface, error) {
+	return domain.Interface{Name: name, Status: s.status}, s.err
+}
+
+func (s *stubNetwork) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	return []domain.Interface{{Name: "awdl0", Status: s.status}}, s.err
+}
+
+func (s *stubNetwork) DisableInterface(_ context.Context, name string) error {
+	s.disables++
+	return s.err
+}
+
+func (s *stubNetwork) EnableInterface(_ context.Context, name string) error {
+	return s.err
+}
+
+func TestFallbackNetworkAdapter(t *testing.T) {
+	tests := []struct {
+		name          string
+		primaryErr    error
+		wantStatus    domain.Status
+		wantFallbacks int
+	}{
+		{"Primary works", nil, domain.StatusUp, 0},
+		{"Primary fails", errors.New("SIOCGIFFLAGS awdl0: operation not supported"), domain.StatusDown, 1},
+		{"Interface missing", domain.ErrInterfaceNotFound, domain.StatusAbsent, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primaryStatus := domain.StatusUp
+			if errors.Is(tt.primaryErr, domain.ErrInterfaceNotFound) {
+				primaryStatus = domain.StatusAbsent
+			}
+			primary := &stubNetwork{status: primaryStatus, err: tt.primaryErr}
+			fallback := &stubNetwork{status: domain.StatusDown}
+			adapter := network.NewFallbackNetworkAdapter(primary, fallback)
+
+			status, _ := adapter.CheckInterface(context.Background(), "awdl0")
+			if status != tt.wantStatus {
+				t.Errorf("Expected %s, got %s", tt.wantStatus, status)
+			}
+			if err := adapter.DisableInterface(context.Background(), "awdl0"); err != nil && tt.wantFallbacks > 0 {
+				t.Errorf("Expected the fallback to disable, got %v", err)
+			}
+			if fallback.checks != tt.wantFallbacks || fallback.disables != tt.wantFallbacks {
+				t.Errorf("Expected %d fallback calls, got %d checks and %d disables",
+					tt.wantFallbacks, fallback.checks, fallback.disables)
+			}
+		})
+	}
+}
+
+func TestFallbackNetworkAdapter_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	primary := &stubNetwork{status: domain.StatusUnknown, err: ctx.Err()}
+	fallback := &stubNetwork{status: domain.StatusDown}
+	adapter := network.NewFallbackNetworkAdapter(primary, fallback)
+
+	if _, err := adapter.CheckInterface(ctx, "awdl0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the cancellation to be returned, got %v", err)
+	}
+	if fallback.checks != 0 {
+		t.Errorf("Expected no fallback once cancelled, got %d checks", fallback.checks)
+	}
+}
@@ -0,0 +1,225 @@
+//go:build darwin || linux
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// IoctlNetworkAdapter reads and sets interface flags in-process through
+// SIOCGIFFLAGS and SIOCSIFFLAGS, without spawning ifconfig. The ioctls
+// return at once, so a context is only checked before each operation.
+type IoctlNetworkAdapter struct {
+	// fd is a datagram socket, only used as a handle for the ioctls
+	fd int
+}
+
+// ifreqFlags is struct ifreq with the flags member of its union. The padding
+// covers the largest union member on every supported system.
+type ifreqFlags struct {
+	Name  [unix.IFNAMSIZ]byte
+	Flags uint16
+	_     [22]byte
+}
+
+// ifaceFlags names the flags shared by macOS and Linux, in ifconfig's order
+var ifaceFlags = []struct {
+	bit  uint16
+	name string
+}{
+	{unix.IFF_UP, "UP"},
+	{unix.IFF_BROADCAST, "BROADCAST"},
+	{unix.IFF_DEBUG, "DEBUG"},
+	{unix.IFF_LOOPBACK, "LOOPBACK"},
+	{unix.IFF_POINTOPOINT, "POINTOPOINT"},
+	{unix.IFF_RUNNING, "RUNNING"},
+	{unix.IFF_NOARP, "NOARP"},
+	{unix.IFF_PROMISC, "PROMISC"},
+	{unix.IFF_ALLMULTI, "ALLMULTI"},
+	{unix.IFF_MULTICAST, "MULTICAST"},
+}
+
+func NewIoctlNetworkAdapter() (*IoctlNetworkAdapter, error) {
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening control socket: %w", err)
+	}
+	return &IoctlNetworkAdapter{fd: fd}, nil
+}
+
+// Close releases the control socket
+func (a *IoctlNetworkAdapter) Close() error {
+	return unix.Close(a.fd)
+}
+
+func (a *IoctlNetworkAdapter) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	if err := ctx.Err(); err != nil {
+		return domain.StatusUnknown, err
+	}
+
+	flags, err := a.flags(name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInterfaceNotFound) {
+			return domain.StatusAbsent, err
+		}
+		return domain.StatusUnknown, err
+	}
+
+	if flags&unix.IFF_UP != 0 {
+		return domain.StatusUp, nil
+	}
+	return domain.StatusDown, nil
+}
+
+// DescribeInterface combines the flags with what the standard library knows
+// about the interface. Media and link status are not available this way.
+func (a *IoctlNetworkAdapter) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	status, err := a.CheckInterface(ctx, name)
+	if err != nil {
+		return domain.Interface{Name: name, Status: status}, err
+	}
+
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return domain.Interface{Name: name, Status: status}, err
+	}
+	return a.describe(*iface)
+}
+
+func (a *IoctlNetworkAdapter) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var described []domain.Interface
+	for _, iface := range ifaces {
+		d, err := a.describe(iface)
+		if err != nil {
+			return nil, err
+		}
+		described = append(described, d)
+	}
+	return described, nil
+}
+
+func (a *IoctlNetworkAdapter) describe(iface net.Interface) (domain.Interface, error) {
+	flags, err := a.flags(iface.Name)
+	if err != nil {
+		return domain.Interface{Name: iface.Name, Status: domain.StatusUnknown}, err
+	}
+
+	d := domain.Interface{
+		Name:   iface.Name,
+		MTU:    iface.MTU,
+		Ether:  iface.HardwareAddr.String(),
+		Status: domain.StatusDown,
+	}
+	for _, f := range ifaceFlags {
+		if flags&f.bit != 0 {
+			d.Flags = append(d.Flags, f.name)
+		}
+	}
+	if flags&unix.IFF_UP != 0 {
+		d.Status = domain.StatusUp
+	}
+
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return d, err
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			d.Inet = append(d.Inet, ipNet.IP.String())
+		} else {
+			d.Inet6 = append(d.Inet6, ipNet.IP.String())
+		}
+	}
+
+	return d, nil
+}
+
+func (a *IoctlNetworkAdapter) DisableInterface(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.setUp(name, false)
+}
+
+func (a *IoctlNetworkAdapter) EnableInterface(ctx context.Context, name string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return a.setUp(name, true)
+}
+
+// setUp sets or clears IFF_UP, leaving every other flag alone
+func (a *IoctlNetworkAdapter) setUp(name string, up bool) error {
+	ifr, err := newIfreq(name)
+	if err != nil {
+		return err
+	}
+	if err := a.ioctl("SIOCGIFFLAGS", unix.SIOCGIFFLAGS, name, &ifr); err != nil {
+		return err
+	}
+
+	if up {
+		ifr.Flags |= unix.IFF_UP
+	} else {
+		ifr.Flags &^= unix.IFF_UP
+	}
+	return a.ioctl("SIOCSIFFLAGS", unix.SIOCSIFFLAGS, name, &ifr)
+}
+
+func (a *IoctlNetworkAdapter) flags(name string) (uint16, error) {
+	ifr, err := newIfreq(name)
+	if err != nil {
+		return 0, err
+	}
+	if err := a.ioctl("SIOCGIFFLAGS", unix.SIOCGIFFLAGS, name, &ifr); err != nil {
+		return 0, err
+	}
+	return ifr.Flags, nil
+}
+
+func newIfreq(name string) (ifreqFlags, error) {
+	var ifr ifreqFlags
+	// The name must leave room for its terminating NUL
+	if len(name) >= len(ifr.Name) {
+		return ifr, fmt.Errorf("interface name %q is too long", name)
+	}
+	copy(ifr.Name[:], name)
+	return ifr, nil
+}
+
+// ioctl issues the request and maps errno values onto the domain errors
+func (a *IoctlNetworkAdapter) ioctl(op string, req uint, name string, ifr *ifreqFlags) error {
+	_, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(a.fd), uintptr(req), uintptr(unsafe.Pointer(ifr)))
+	if errno == 0 {
+		return nil
+	}
+
+	switch errno {
+	case unix.ENXIO, unix.ENODEV:
+		return fmt.Errorf("%s %s: %w", op, name, domain.ErrInterfaceNotFound)
+	case unix.EPERM, unix.EACCES:
+		return fmt.Errorf("%s %s: %w", op, name, domain.ErrPermissionDenied)
+	}
+	return fmt.Errorf("%s %s: %w", op, name, errno)
+}
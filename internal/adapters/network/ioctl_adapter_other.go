@@ -0,0 +1,41 @@
+//go:build !darwin && !linux
+
+package network
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+var errIoctlUnsupported = errors.New("the ioctl backend is not supported on this system")
+
+// IoctlNetworkAdapter is only available on macOS and Linux
+type IoctlNetworkAdapter struct{}
+
+func NewIoctlNetworkAdapter() (*IoctlNetworkAdapter, error) {
+	return nil, errIoctlUnsupported
+}
+
+func (a *IoctlNetworkAdapter) Close() error { return nil }
+
+func (a *IoctlNetworkAdapter) CheckInterface(_ context.Context, name string) (domain.Status, error) {
+	return domain.StatusUnknown, errIoctlUnsupported
+}
+
+func (a *IoctlNetworkAdapter) DescribeInterface(_ context.Context, name string) (domain.Interface, error) {
+	return domain.Interface{Name: name, Status: domain.StatusUnknown}, errIoctlUnsupported
+}
+
+func (a *IoctlNetworkAdapter) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	return nil, errIoctlUnsupported
+}
+
+func (a *IoctlNetworkAdapter) DisableInterface(_ context.Context, name string) error {
+	return errIoctlUnsupported
+}
+
+func (a *IoctlNetworkAdapter) EnableInterface(_ context.Context, name string) error {
+	return errIoctlUnsupported
+}
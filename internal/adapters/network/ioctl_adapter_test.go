@@ -0,0 +1,80 @@
+//go:build darwin || linux
+
+package network_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// loopback is the loopback interface, which is UP on every test machine
+func loopback() string {
+	if runtime.GOOS == "darwin" {
+		return "lo0"
+	}
+	return "lo"
+}
+
+func newIoctlAdapter(tb testing.TB) *network.IoctlNetworkAdapter {
+	tb.Helper()
+	adapter, err := network.NewIoctlNetworkAdapter()
+	if err != nil {
+		tb.Fatalf("Failed to create the ioctl adapter: %v", err)
+	}
+	tb.Cleanup(func() { adapter.Close() })
+	return adapter
+}
+
+func TestIoctlNetworkAdapter_CheckInterface(t *testing.T) {
+	adapter := newIoctlAdapter(t)
+
+	status, err := adapter.CheckInterface(context.Background(), loopback())
+	if err != nil || status != domain.StatusUp {
+		t.Errorf("Expected the loopback to be UP, got %v, %v", status, err)
+	}
+
+	status, err = adapter.CheckInterface(context.Background(), "nosuchif0")
+	if !errors.Is(err, domain.ErrInterfaceNotFound) || status != domain.StatusAbsent {
+		t.Errorf("Expected a missing interface to be Absent, got %v, %v", status, err)
+	}
+}
+
+func TestIoctlNetworkAdapter_DescribeInterface(t *testing.T) {
+	adapter := newIoctlAdapter(t)
+
+	iface, err := adapter.DescribeInterface(context.Background(), loopback())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !iface.HasFlag("UP") || !iface.HasFlag("LOOPBACK") || iface.MTU == 0 {
+		t.Errorf("Expected an UP loopback with an MTU, got %+v", iface)
+	}
+}
+
+func BenchmarkIoctlNetworkAdapter_CheckInterface(b *testing.B) {
+	adapter := newIoctlAdapter(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := adapter.CheckInterface(context.Background(), loopback()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkShellNetworkAdapter_CheckInterface(b *testing.B) {
+	if _, err := exec.LookPath("ifconfig"); err != nil {
+		b.Skip("ifconfig is not installed")
+	}
+
+	adapter := network.NewShellNetworkAdapter()
+	for i := 0; i < b.N; i++ {
+		if _, err := adapter.CheckInterface(context.Background(), loopback()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// IPNetworkAdapter manages Linux links with iproute2, reading the JSON output
+// of ip -j addr. Like the shell adapter, changes go through sudo -n unless the
+// process already runs as root.
+type IPNetworkAdapter struct {
+	run    CommandRunner
+	binary string
+	sudo   string
+	root   bool
+	// netns is the named network namespace to work in, empty for the
+	// current one
+	netns string
+}
+
+func NewIPNetworkAdapter() *IPNetworkAdapter {
+	return &IPNetworkAdapter{
+		run:    ExecRunner,
+		binary: "ip",
+		sudo:   "sudo",
+		root:   os.Geteuid() == 0,
+	}
+}
+
+// WithRunner replaces the way commands are run, e.g. by a fake in tests
+func (a *IPNetworkAdapter) WithRunner(run CommandRunner) *IPNetworkAdapter {
+	a.run = run
+	return a
+}
+
+// WithBinary sets the ip binary. An empty value keeps the current one.
+func (a *IPNetworkAdapter) WithBinary(ip string) *IPNetworkAdapter {
+	if ip != "" {
+		a.binary = ip
+	}
+	return a
+}
+
+// WithSudo sets the sudo binary. An empty value keeps the current one.
+func (a *IPNetworkAdapter) WithSudo(sudo string) *IPNetworkAdapter {
+	if sudo != "" {
+		a.sudo = sudo
+	}
+	return a
+}
+
+// WithRoot overrides whether the process runs as root, and so whether
+// changes need sudo
+func (a *IPNetworkAdapter) WithRoot(root bool) *IPNetworkAdapter {
+	a.root = root
+	return a
+}
+
+// InNamespace makes the adapter manage the links of a named network
+// namespace, as ip -n does
+func (a *IPNetworkAdapter) InNamespace(name string) *IPNetworkAdapter {
+	a.netns = name
+	return a
+}
+
+func (a *IPNetworkAdapter) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	iface, err := a.DescribeInterface(ctx, name)
+	return iface.Status, err
+}
+
+// DescribeInterface reports a missing link as StatusAbsent together with
+// ErrInterfaceNotFound, like the other adapters
+func (a *IPNetworkAdapter) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	output, err := a.ip(ctx, false, "-j", "addr", "show", "dev", name)
+	if err != nil {
+		if errors.Is(err, domain.ErrInterfaceNotFound) {
+			return domain.Interface{Name: name, Status: domain.StatusAbsent}, err
+		}
+		return domain.Interface{Name: name, Status: domain.StatusUnknown}, err
+	}
+
+	ifaces, err := ParseIPLinks(output)
+	if err != nil {
+		return domain.Interface{Name: name, Status: domain.StatusUnknown}, err
+	}
+	for _, iface := range ifaces {
+		if iface.Name == name {
+			return iface, nil
+		}
+	}
+	return domain.Interface{Name: name, Status: domain.StatusAbsent}, fmt.Errorf("ip link %s: %w", name, domain.ErrInterfaceNotFound)
+}
+
+func (a *IPNetworkAdapter) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	output, err := a.ip(ctx, false, "-j", "addr", "show")
+	if err != nil {
+		return nil, err
+	}
+	return ParseIPLinks(output)
+}
+
+func (a *IPNetworkAdapter) DisableInterface(ctx context.Context, name string) error {
+	_, err := a.ip(ctx, true, "link", "set", "dev", name, "down")
+	return err
+}
+
+func (a *IPNetworkAdapter) EnableInterface(ctx context.Context, name string) error {
+	_, err := a.ip(ctx, true, "link", "set", "dev", name, "up")
+	return err
+}
+
+// ip runs ip with the given arguments, through sudo -n when it changes
+// something and the process is not root
+func (a *IPNetworkAdapter) ip(ctx context.Context, privileged bool, args ...string) (string, error) {
+	if a.netns != "" {
+		args = append([]string{"-n", a.netns}, args...)
+	}
+	if privileged && !a.root {
+		return a.run(ctx, a.sudo, append([]string{"-n", a.binary}, args...)...)
+	}
+
+	return a.run(ctx, a.binary, args...)
+}
+
+// ipLink is one entry of ip -j addr
+type ipLink struct {
+	Name      string   `json:"ifname"`
+	Flags     []string `json:"flags"`
+	MTU       int      `json:"mtu"`
+	OperState string   `json:"operstate"`
+	LinkType  string   `json:"link_type"`
+	Address   string   `json:"address"`
+	AddrInfo  []struct {
+		Family string `json:"family"`
+		Local  string `json:"local"`
+	} `json:"addr_info"`
+}
+
+// ParseIPLinks turns the output of ip -j addr into interfaces. The UP flag
+// is the administrative state the adapter changes; the operational state is
+// reported as the link status.
+func ParseIPLinks(output string) ([]domain.Interface, error) {
+	var links []ipLink
+	if err := json.Unmarshal([]byte(output), &links); err != nil {
+		return nil, fmt.Errorf("parsing ip output: %w", err)
+	}
+
+	ifaces := make([]domain.Interface, 0, len(links))
+	for _, link := range links {
+		iface := domain.Interface{
+			Name:       link.Name,
+			Flags:      link.Flags,
+			MTU:        link.MTU,
+			Status:     domain.StatusDown,
+			LinkStatus: strings.ToLower(link.OperState),
+			Media:      link.LinkType,
+		}
+		if link.LinkType == "ether" {
+			iface.Ether = link.Address
+		}
+		if iface.HasFlag("UP") {
+			iface.Status = domain.StatusUp
+		}
+
+		for _, addr := range link.AddrInfo {
+			switch addr.Family {
+			case "inet":
+				iface.Inet = append(iface.Inet, addr.Local)
+			case "inet6":
+				iface.Inet6 = append(iface.Inet6, addr.Local)
+			}
+		}
+
+		ifaces = append(ifaces, iface)
+	}
+	return ifaces, nil
+}
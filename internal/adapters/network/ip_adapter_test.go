@@ -0,0 +1,148 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestParseIPLinks(t *testing.T) {
+	ifaces, err := network.ParseIPLinks(readFixture(t, "ip_addr.json"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ifaces) != 4 {
+		t.Fatalf("Expected 4 links, got %d", len(ifaces))
+	}
+
+	p2p := ifaces[2]
+	if p2p.Name != "p2p-wlan0-0" || p2p.Status != domain.StatusUp || p2p.Ether != "a6:c3:f0:12:34:57" {
+		t.Errorf("Unexpected p2p link %+v", p2p)
+	}
+	if len(p2p.Inet6) != 1 || p2p.Inet6[0] != "fe80::a4c3:f0ff:fe12:3457" || p2p.LinkStatus != "up" {
+		t.Errorf("Unexpected p2p addresses or state %+v", p2p)
+	}
+
+	dummy := ifaces[3]
+	if dummy.Status != domain.StatusDown || dummy.HasFlag("UP") || dummy.LinkStatus != "down" {
+		t.Errorf("Expected dummy0 to be DOWN, got %+v", dummy)
+	}
+
+	if lo := ifaces[0]; lo.Ether != "" || len(lo.Inet) != 1 || lo.Media != "loopback" {
+		t.Errorf("Expected the loopback without a MAC, got %+v", lo)
+	}
+
+	if _, err := network.ParseIPLinks("Device \"x\" does not exist."); err == nil {
+		t.Error("Expected output that is not JSON to be rejected")
+	}
+}
+
+// newNamespace creates a network namespace for the test, skipping the test
+// where that is not possible
+func newNamespace(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS != "linux" {
+		t.Skip("network namespaces need Linux")
+	}
+	if os.Geteuid() != 0 {
+		t.Skip("creating network namespaces needs root")
+	}
+	if _, err := exec.LookPath("ip"); err != nil {
+		t.Skip("iproute2 is not installed")
+	}
+
+	name := fmt.Sprintf("awdl-mon-test-%d", os.Getpid())
+	if output, err := exec.Command("ip", "netns", "add", name).CombinedOutput(); err != nil {
+		t.Skipf("cannot create a network namespace: %v: %s", err, output)
+	}
+	t.Cleanup(func() { exec.Command("ip", "netns", "del", name).Run() })
+	return name
+}
+
+func TestIPNetworkAdapter_Commands(t *testing.T) {
+	tests := []struct {
+		name   string
+		root   bool
+		netns  string
+		binary string
+		want   []string
+	}{
+		{"As root", true, "", "", []string{"ip -j addr show dev wlan0", "ip link set dev wlan0 down", "ip link set dev wlan0 up"}},
+		{"As user", false, "", "", []string{"ip -j addr show dev wlan0", "sudo -n ip link set dev wlan0 down", "sudo -n ip link set dev wlan0 up"}},
+		{"In a namespace", false, "test", "", []string{
+			"ip -n test -j addr show dev wlan0", "sudo -n ip -n test link set dev wlan0 down", "sudo -n ip -n test link set dev wlan0 up",
+		}},
+		{"Fixed binary", true, "", "/usr/sbin/ip", []string{
+			"/usr/sbin/ip -j addr show dev wlan0", "/usr/sbin/ip link set dev wlan0 down", "/usr/sbin/ip link set dev wlan0 up",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingRunner{output: "[]"}
+			adapter := network.NewIPNetworkAdapter().WithRunner(runner.run).WithRoot(tt.root).InNamespace(tt.netns).WithBinary(tt.binary)
+
+			adapter.CheckInterface(context.Background(), "wlan0")
+			adapter.DisableInterface(context.Background(), "wlan0")
+			adapter.EnableInterface(context.Background(), "wlan0")
+
+			if strings.Join(runner.commands, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("Expected commands %q, got %q", tt.want, runner.commands)
+			}
+		})
+	}
+}
+
+func TestIPNetworkAdapter_Namespace(t *testing.T) {
+	ns := newNamespace(t)
+	adapter := network.NewIPNetworkAdapter().InNamespace(ns)
+
+	// Every namespace has a loopback that starts DOWN; dummy interfaces need
+	// the dummy module
+	links := []string{"lo"}
+	if err := exec.Command("ip", "-n", ns, "link", "add", "dummy0", "type", "dummy").Run(); err == nil {
+		links = append(links, "dummy0")
+	} else {
+		t.Logf("dummy interfaces are not available, testing the loopback only: %v", err)
+	}
+
+	for _, name := range links {
+		t.Run(name, func(t *testing.T) {
+			if status, err := adapter.CheckInterface(context.Background(), name); err != nil || status != domain.StatusDown {
+				t.Fatalf("Expected %s to start DOWN, got %v, %v", name, status, err)
+			}
+
+			if err := adapter.EnableInterface(context.Background(), name); err != nil {
+				t.Fatalf("Failed to enable: %v", err)
+			}
+			if status, err := adapter.CheckInterface(context.Background(), name); err != nil || status != domain.StatusUp {
+				t.Errorf("Expected %s to be UP, got %v, %v", name, status, err)
+			}
+
+			if err := adapter.DisableInterface(context.Background(), name); err != nil {
+				t.Fatalf("Failed to disable: %v", err)
+			}
+			if status, err := adapter.CheckInterface(context.Background(), name); err != nil || status != domain.StatusDown {
+				t.Errorf("Expected %s to be DOWN again, got %v, %v", name, status, err)
+			}
+		})
+	}
+
+	ifaces, err := adapter.ListInterfaces(context.Background())
+	if err != nil || len(ifaces) != len(links) {
+		t.Errorf("Expected %d links in the namespace, got %v, %v", len(links), ifaces, err)
+	}
+
+	status, err := adapter.CheckInterface(context.Background(), "p2p-dev-wlan0")
+	if !errors.Is(err, domain.ErrInterfaceNotFound) || status != domain.StatusAbsent {
+		t.Errorf("Expected a missing link to be Absent, got %v, %v", status, err)
+	}
+}
@@ -1,31 +1,111 @@
 package network
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
-// ParseInterfaceStatus analyzes ifconfig output to determine if interface is UP
-func ParseInterfaceStatus(output string) domain.Status {
-	lines := strings.Split(output, "\n")
+// ParseInterfaceStatus analyzes ifconfig output to determine if the named
+// interface is UP. Output without a block for it is StatusUnknown.
+func ParseInterfaceStatus(output, name string) domain.Status {
+	iface, ok := ParseInterface(output, name)
+	if !ok {
+		return domain.StatusUnknown
+	}
+	return iface.Status
+}
+
+// ParseInterface returns the block of the named interface from the output of
+// ifconfig or ifconfig -a
+func ParseInterface(output, name string) (domain.Interface, bool) {
+	for _, iface := range ParseInterfaces(output) {
+		if iface.Name == name {
+			return iface, true
+		}
+	}
+	return domain.Interface{}, false
+}
+
+// ParseInterfaces turns the output of ifconfig -a into one Interface per
+// block. A block starts with an unindented "<name>: flags=" line; the
+// indented lines below it describe the same interface.
+func ParseInterfaces(output string) []domain.Interface {
+	var ifaces []domain.Interface
+	var current *domain.Interface
 
-	for _, line := range lines {
-		if !strings.Contains(line, "flags=") {
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
 			continue
 		}
 
-		start := strings.Index(line, "<")
-		end := strings.Index(line, ">")
+		if line[0] != ' ' && line[0] != '\t' {
+			name, rest, ok := strings.Cut(line, ": ")
+			if !ok || !strings.HasPrefix(rest, "flags=") {
+				current = nil
+				continue
+			}
 
-		if start == -1 || end == -1 || start >= end {
+			ifaces = append(ifaces, parseHeader(name, rest))
+			current = &ifaces[len(ifaces)-1]
 			continue
 		}
 
-		flags := line[start+1 : end]
-		if strings.Contains(flags, "UP") {
-			return domain.StatusUp
+		if current != nil {
+			parseDetail(current, strings.Fields(line))
 		}
 	}
-	return domain.StatusDown
+
+	return ifaces
+}
+
+// ParseInterfaceList splits the output of ifconfig -l, a single line of
+// space separated names
+func ParseInterfaceList(output string) []string {
+	return strings.Fields(output)
+}
+
+// parseHeader parses "flags=8863<UP,BROADCAST> mtu 1500"
+func parseHeader(name, rest string) domain.Interface {
+	iface := domain.Interface{Name: name, Status: domain.StatusDown}
+
+	if start, end := strings.Index(rest, "<"), strings.Index(rest, ">"); start != -1 && start < end {
+		if flags := rest[start+1 : end]; flags != "" {
+			iface.Flags = strings.Split(flags, ",")
+		}
+	}
+	if iface.HasFlag("UP") {
+		iface.Status = domain.StatusUp
+	}
+
+	fields := strings.Fields(rest)
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == "mtu" {
+			iface.MTU, _ = strconv.Atoi(fields[i+1])
+		}
+	}
+
+	return iface
+}
+
+// parseDetail fills in one indented line of a block
+func parseDetail(iface *domain.Interface, fields []string) {
+	if len(fields) < 2 {
+		return
+	}
+
+	switch fields[0] {
+	case "ether":
+		iface.Ether = fields[1]
+	case "inet":
+		iface.Inet = append(iface.Inet, fields[1])
+	case "inet6":
+		addr, _, _ := strings.Cut(fields[1], "%")
+		iface.Inet6 = append(iface.Inet6, addr)
+	case "media:":
+		iface.Media = strings.Join(fields[1:], " ")
+	case "status:":
+		iface.LinkStatus = fields[1]
+	}
 }
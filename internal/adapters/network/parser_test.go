@@ -1,9 +1,13 @@
 package network_test
 
 import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
 	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
-	"testing"
 )
 
 func TestParseInterfaceStatus(t *testing.T) {
@@ -25,21 +29,129 @@ func TestParseInterfaceStatus(t *testing.T) {
 		{
 			name:     "Empty Output",
 			output:   "",
-			expected: domain.StatusDown,
+			expected: domain.StatusUnknown,
+		},
+		{
+			name:     "No Flags",
+			output:   "ifconfig: interface awdl0 does not exist",
+			expected: domain.StatusUnknown,
 		},
 		{
 			name:     "Complex Output",
 			output:   "lo0: flags=8049<UP,LOOPBACK,RUNNING,MULTICAST> mtu 16384\nawdl0: flags=8051<UP,POINTOPOINT,RUNNING,MULTICAST> mtu 1500",
 			expected: domain.StatusUp,
 		},
+		{
+			name:     "Other Interface UP",
+			output:   "lo0: flags=8049<UP,LOOPBACK,RUNNING,MULTICAST> mtu 16384\nawdl0: flags=8050<POINTOPOINT,RUNNING,MULTICAST> mtu 1500",
+			expected: domain.StatusDown,
+		},
+		{
+			name:     "Interface Missing",
+			output:   "lo0: flags=8049<UP,LOOPBACK,RUNNING,MULTICAST> mtu 16384",
+			expected: domain.StatusUnknown,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := network.ParseInterfaceStatus(tt.output)
+			got := network.ParseInterfaceStatus(tt.output, "awdl0")
 			if got != tt.expected {
 				t.Errorf("ParseInterfaceStatus() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
 }
+
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	return string(data)
+}
+
+func TestParseInterfaces_AllInterfaces(t *testing.T) {
+	ifaces := network.ParseInterfaces(readFixture(t, "ifconfig_a.txt"))
+
+	var names []string
+	for _, iface := range ifaces {
+		names = append(names, iface.Name)
+	}
+	want := []string{"lo0", "gif0", "stf0", "anpi0", "en0", "awdl0", "llw0", "utun0"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("Expected interfaces %v, got %v", want, names)
+	}
+
+	tests := []domain.Interface{
+		{
+			Name:   "lo0",
+			Flags:  []string{"UP", "LOOPBACK", "RUNNING", "MULTICAST"},
+			MTU:    16384,
+			Inet:   []string{"127.0.0.1"},
+			Inet6:  []string{"::1", "fe80::1"},
+			Status: domain.StatusUp,
+		},
+		{
+			Name:   "stf0",
+			MTU:    1280,
+			Status: domain.StatusDown,
+		},
+		{
+			Name:       "en0",
+			Flags:      []string{"UP", "BROADCAST", "SMART", "RUNNING", "SIMPLEX", "MULTICAST"},
+			MTU:        1500,
+			Ether:      "3c:22:fb:12:34:56",
+			Inet:       []string{"192.168.1.23"},
+			Inet6:      []string{"fe80::1c8f:4a2b:9d3e:7f10", "2001:db8:1::4c1a:5e2f:1b2c:3d4e"},
+			Status:     domain.StatusUp,
+			LinkStatus: "active",
+			Media:      "autoselect",
+		},
+		{
+			Name:       "awdl0",
+			Flags:      []string{"UP", "BROADCAST", "RUNNING", "PROMISC", "SIMPLEX", "MULTICAST"},
+			MTU:        1500,
+			Ether:      "6e:7e:67:aa:bb:cc",
+			Inet6:      []string{"fe80::6c7e:67ff:feaa:bbcc"},
+			Status:     domain.StatusUp,
+			LinkStatus: "active",
+			Media:      "autoselect",
+		},
+	}
+
+	for _, want := range tests {
+		got, ok := network.ParseInterface(readFixture(t, "ifconfig_a.txt"), want.Name)
+		if !ok {
+			t.Errorf("Expected %s to be parsed", want.Name)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Parsed %s as %+v, want %+v", want.Name, got, want)
+		}
+	}
+}
+
+func TestParseInterface_Down(t *testing.T) {
+	iface, ok := network.ParseInterface(readFixture(t, "ifconfig_awdl0_down.txt"), "awdl0")
+	if !ok {
+		t.Fatal("Expected awdl0 to be parsed")
+	}
+
+	if iface.Status != domain.StatusDown || iface.HasFlag("UP") || !iface.HasFlag("PROMISC") {
+		t.Errorf("Expected awdl0 DOWN with PROMISC, got %+v", iface)
+	}
+	if iface.LinkStatus != "inactive" || iface.Inet6 != nil {
+		t.Errorf("Expected an inactive link without addresses, got %+v", iface)
+	}
+}
+
+func TestParseInterfaceList(t *testing.T) {
+	names := network.ParseInterfaceList("lo0 gif0 stf0 en0 awdl0 llw0 utun0\n")
+
+	want := []string{"lo0", "gif0", "stf0", "en0", "awdl0", "llw0", "utun0"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
@@ -0,0 +1,65 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// CommandRunner runs a command and returns its standard output. Failures are
+// returned as *domain.CommandError.
+type CommandRunner func(ctx context.Context, name string, args ...string) (string, error)
+
+// ExecRunner runs commands with os/exec, killing them when ctx is done
+func ExecRunner(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return stdout.String(), nil
+	}
+
+	cmdErr := &domain.CommandError{
+		Command:  strings.Join(append([]string{name}, args...), " "),
+		ExitCode: -1,
+		Stderr:   stderr.String(),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() != nil:
+		cmdErr.Kind = ctx.Err()
+	case errors.As(err, &exitErr):
+		cmdErr.ExitCode = exitErr.ExitCode()
+		cmdErr.Kind = ClassifyFailure(stderr.String())
+	default:
+		// The command could not be started at all
+		cmdErr.Stderr = err.Error()
+	}
+
+	return stdout.String(), cmdErr
+}
+
+// ClassifyFailure recognizes the error messages of ifconfig, ip and sudo. It
+// returns nil when the failure has no more specific kind.
+func ClassifyFailure(stderr string) error {
+	msg := strings.ToLower(stderr)
+
+	switch {
+	case strings.Contains(msg, "does not exist"), strings.Contains(msg, "no such interface"),
+		strings.Contains(msg, "device not configured"), strings.Contains(msg, "cannot find device"):
+		return domain.ErrInterfaceNotFound
+	case strings.Contains(msg, "permission denied"), strings.Contains(msg, "operation not permitted"),
+		strings.Contains(msg, "must be root"), strings.Contains(msg, "a password is required"),
+		strings.Contains(msg, "a terminal is required"):
+		return domain.ErrPermissionDenied
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestExecRunner_Failure(t *testing.T) {
+	_, err := network.ExecRunner(context.Background(), "sh", "-c", "echo 'ifconfig: interface awdl9 does not exist' >&2; exit 3")
+
+	var cmdErr *domain.CommandError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("Expected a CommandError, got %v", err)
+	}
+	if cmdErr.ExitCode != 3 || cmdErr.Stderr != "ifconfig: interface awdl9 does not exist\n" {
+		t.Errorf("Expected exit code 3 and the stderr, got %d and %q", cmdErr.ExitCode, cmdErr.Stderr)
+	}
+	if cmdErr.Command != "sh -c echo 'ifconfig: interface awdl9 does not exist' >&2; exit 3" {
+		t.Errorf("Expected the full command, got %q", cmdErr.Command)
+	}
+	if !errors.Is(err, domain.ErrInterfaceNotFound) {
+		t.Error("Expected the failure to be classified")
+	}
+}
+
+func TestExecRunner_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := network.ExecRunner(ctx, "sleep", "5")
+
+	if !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, domain.ErrCommandFailed) {
+		t.Errorf("Expected a timed out command error, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Error("Expected the command to be killed at the deadline")
+	}
+}
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		stderr   string
+		expected error
+	}{
+		{"ifconfig: interface awdl9 does not exist\n", domain.ErrInterfaceNotFound},
+		{"Cannot find device \"p2p-dev-wlan0\"\n", domain.ErrInterfaceNotFound},
+		{"RTNETLINK answers: Operation not permitted\n", domain.ErrPermissionDenied},
+		{"ifconfig: ioctl (SIOCSIFFLAGS): Operation not permitted\n", domain.ErrPermissionDenied},
+		{"ifconfig: ioctl (SIOCSIFFLAGS): Permission denied\n", domain.ErrPermissionDenied},
+		{"sudo: a password is required\n", domain.ErrPermissionDenied},
+		{"ifconfig: unexpected failure\n", nil},
+	}
+
+	for _, tt := range tests {
+		if got := network.ClassifyFailure(tt.stderr); got != tt.expected {
+			t.Errorf("ClassifyFailure(%q) = %v, want %v", tt.stderr, got, tt.expected)
+		}
+	}
+}
@@ -1,33 +1,125 @@
 package network
 
 import (
-	"os/exec"
+	"context"
+	"errors"
+	"fmt"
+	"os"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
-type ShellNetworkAdapter struct{}
+// ShellNetworkAdapter manages interfaces by running ifconfig. Changes go
+// through sudo -n unless the process already runs as root, so sudo never
+// prompts for a password.
+type ShellNetworkAdapter struct {
+	run      CommandRunner
+	ifconfig string
+	sudo     string
+	root     bool
+}
 
 func NewShellNetworkAdapter() *ShellNetworkAdapter {
-	return &ShellNetworkAdapter{}
+	return &ShellNetworkAdapter{
+		run:      ExecRunner,
+		ifconfig: "ifconfig",
+		sudo:     "sudo",
+		root:     os.Geteuid() == 0,
+	}
+}
+
+// WithRunner replaces the way commands are run, e.g. by a fake in tests
+func (a *ShellNetworkAdapter) WithRunner(run CommandRunner) *ShellNetworkAdapter {
+	a.run = run
+	return a
+}
+
+// WithBinaries sets the ifconfig and sudo binaries. Empty values keep the
+// current ones.
+func (a *ShellNetworkAdapter) WithBinaries(ifconfig, sudo string) *ShellNetworkAdapter {
+	if ifconfig != "" {
+		a.ifconfig = ifconfig
+	}
+	if sudo != "" {
+		a.sudo = sudo
+	}
+	return a
+}
+
+// WithRoot overrides whether the process runs as root, and so whether
+// changes need sudo
+func (a *ShellNetworkAdapter) WithRoot(root bool) *ShellNetworkAdapter {
+	a.root = root
+	return a
 }
 
-func (a *ShellNetworkAdapter) CheckInterface(name string) (domain.Status, error) {
-	cmd := exec.Command("ifconfig", name)
-	output, err := cmd.CombinedOutput()
+// CheckInterface returns StatusAbsent together with ErrInterfaceNotFound when
+// the interface does not exist, and StatusUnknown for any other failure
+func (a *ShellNetworkAdapter) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	iface, err := a.DescribeInterface(ctx, name)
+	return iface.Status, err
+}
+
+// DescribeInterface parses the interface's ifconfig block. Like
+// CheckInterface it reports a missing interface as StatusAbsent.
+func (a *ShellNetworkAdapter) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	output, err := a.command(ctx, false, name)
 	if err != nil {
-		return domain.StatusDown, err
+		if errors.Is(err, domain.ErrInterfaceNotFound) {
+			return domain.Interface{Name: name, Status: domain.StatusAbsent}, err
+		}
+		return domain.Interface{Name: name, Status: domain.StatusUnknown}, err
 	}
 
-	return ParseInterfaceStatus(string(output)), nil
+	iface, ok := ParseInterface(output, name)
+	if !ok {
+		return domain.Interface{Name: name, Status: domain.StatusAbsent}, fmt.Errorf("ifconfig %s: %w", name, domain.ErrInterfaceNotFound)
+	}
+	return iface, nil
 }
 
-func (a *ShellNetworkAdapter) DisableInterface(name string) error {
-	cmd := exec.Command("sudo", "ifconfig", name, "down")
-	return cmd.Run()
+// ListInterfaces parses ifconfig -a. Should that not yield any block, the
+// names from ifconfig -l are returned with an unknown status.
+func (a *ShellNetworkAdapter) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	output, err := a.command(ctx, false, "-a")
+	if err != nil {
+		return nil, err
+	}
+	if ifaces := ParseInterfaces(output); len(ifaces) > 0 {
+		return ifaces, nil
+	}
+
+	output, err = a.command(ctx, false, "-l")
+	if err != nil {
+		return nil, err
+	}
+
+	var ifaces []domain.Interface
+	for _, name := range ParseInterfaceList(output) {
+		ifaces = append(ifaces, domain.Interface{Name: name, Status: domain.StatusUnknown})
+	}
+	return ifaces, nil
 }
 
-func (a *ShellNetworkAdapter) EnableInterface(name string) error {
-	cmd := exec.Command("sudo", "ifconfig", name, "up")
-	return cmd.Run()
+func (a *ShellNetworkAdapter) DisableInterface(ctx context.Context, name string) error {
+	_, err := a.command(ctx, true, name, "down")
+	return err
+}
+
+func (a *ShellNetworkAdapter) EnableInterface(ctx context.Context, name string) error {
+	_, err := a.command(ctx, true, name, "up")
+	return err
+}
+
+// command runs ifconfig with the given arguments, through sudo -n when it
+// changes something and the process is not root. It is killed once ctx is
+// done.
+func (a *ShellNetworkAdapter) command(ctx context.Context, privileged bool, args ...string) (string, error) {
+	name := a.ifconfig
+	if privileged && !a.root {
+		args = append([]string{"-n", a.ifconfig}, args...)
+		name = a.sudo
+	}
+
+	return a.run(ctx, name, args...)
 }
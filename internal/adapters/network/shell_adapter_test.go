@@ -0,0 +1,99 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/network"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// recordingRunner records every command and answers with the given output
+type recordingRunner struct {
+	commands []string
+	output   string
+	err      error
+	deadline bool
+}
+
+func (r *recordingRunner) run(ctx context.Context, name string, args ...string) (string, error) {
+	r.commands = append(r.commands, strings.Join(append([]string{name}, args...), " "))
+	_, r.deadline = ctx.Deadline()
+	return r.output, r.err
+}
+
+func TestShellNetworkAdapter_Commands(t *testing.T) {
+	tests := []struct {
+		name     string
+		root     bool
+		ifconfig string
+		sudo     string
+		want     []string
+	}{
+		{"As root", true, "", "", []string{"ifconfig awdl0", "ifconfig awdl0 down", "ifconfig awdl0 up"}},
+		{"As user", false, "", "", []string{"ifconfig awdl0", "sudo -n ifconfig awdl0 down", "sudo -n ifconfig awdl0 up"}},
+		{"Custom binaries", false, "/sbin/ifconfig", "/usr/bin/doas", []string{
+			"/sbin/ifconfig awdl0", "/usr/bin/doas -n /sbin/ifconfig awdl0 down", "/usr/bin/doas -n /sbin/ifconfig awdl0 up",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &recordingRunner{output: "awdl0: flags=8863<UP,BROADCAST> mtu 1500\n"}
+			adapter := network.NewShellNetworkAdapter().
+				WithRunner(runner.run).
+				WithBinaries(tt.ifconfig, tt.sudo).
+				WithRoot(tt.root)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if status, err := adapter.CheckInterface(ctx, "awdl0"); err != nil || status != domain.StatusUp {
+				t.Errorf("Expected UP, got %v, %v", status, err)
+			}
+			adapter.DisableInterface(ctx, "awdl0")
+			adapter.EnableInterface(ctx, "awdl0")
+
+			if strings.Join(runner.commands, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("Expected commands %q, got %q", tt.want, runner.commands)
+			}
+			if !runner.deadline {
+				t.Error("Expected commands to run with the caller's deadline")
+			}
+		})
+	}
+}
+
+func TestShellNetworkAdapter_Errors(t *testing.T) {
+	notFound := &domain.CommandError{Command: "ifconfig awdl0", ExitCode: 1, Kind: domain.ErrInterfaceNotFound}
+	runner := &recordingRunner{err: notFound}
+	adapter := network.NewShellNetworkAdapter().WithRunner(runner.run)
+
+	status, err := adapter.CheckInterface(context.Background(), "awdl0")
+	if status != domain.StatusAbsent || !errors.Is(err, domain.ErrInterfaceNotFound) {
+		t.Errorf("Expected Absent, got %v, %v", status, err)
+	}
+
+	// ifconfig may succeed without printing the interface
+	runner.err = nil
+	runner.output = "en0: flags=8863<UP,BROADCAST,SMART,RUNNING,SIMPLEX,MULTICAST> mtu 1500\n"
+	status, err = adapter.CheckInterface(context.Background(), "awdl0")
+	if status != domain.StatusAbsent || !errors.Is(err, domain.ErrInterfaceNotFound) {
+		t.Errorf("Expected Absent when ifconfig prints no block for it, got %v, %v", status, err)
+	}
+	iface, err := adapter.DescribeInterface(context.Background(), "awdl0")
+	if iface.Status != domain.StatusAbsent || !errors.Is(err, domain.ErrInterfaceNotFound) {
+		t.Errorf("Expected Absent when ifconfig prints no block for it, got %v, %v", iface.Status, err)
+	}
+
+	runner.err = &domain.CommandError{Command: "sudo -n ifconfig awdl0 down", ExitCode: 1, Stderr: "sudo: a password is required", Kind: domain.ErrPermissionDenied}
+	err = adapter.DisableInterface(context.Background(), "awdl0")
+
+	var cmdErr *domain.CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Stderr != "sudo: a password is required" {
+		t.Errorf("Expected the stderr to be kept, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package persistence
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -18,13 +19,13 @@ func NewMemoryEventRepo() *MemoryEventRepo {
 	}
 }
 
-func (r *MemoryEventRepo) Add(event domain.Event) {
+func (r *MemoryEventRepo) Add(_ context.Context, event domain.Event) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.events = append(r.events, event)
 }
 
-func (r *MemoryEventRepo) GetRecent(duration time.Duration) []domain.Event {
+func (r *MemoryEventRepo) GetRecent(_ context.Context, duration time.Duration) []domain.Event {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
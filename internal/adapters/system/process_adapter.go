@@ -0,0 +1,57 @@
+package system
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CommandRunner runs a command and returns its standard output
+type CommandRunner func(name string, args ...string) ([]byte, error)
+
+// ExecRunner runs commands with os/exec
+func ExecRunner(name string, args ...string) ([]byte, error) {
+	return exec.Command(name, args...).Output()
+}
+
+// PsProcessAdapter lists running processes by parsing ps output
+type PsProcessAdapter struct {
+	run CommandRunner
+}
+
+func NewPsProcessAdapter(run CommandRunner) *PsProcessAdapter {
+	if run == nil {
+		run = ExecRunner
+	}
+	return &PsProcessAdapter{run: run}
+}
+
+func (a *PsProcessAdapter) RunningProcesses() ([]string, error) {
+	output, err := a.run("ps", "-A", "-o", "comm=")
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseProcessNames(string(output)), nil
+}
+
+// ParseProcessNames turns one command per line into executable names. macOS
+// prints the full path of each executable, which may contain spaces.
+func ParseProcessNames(output string) []string {
+	seen := make(map[string]bool)
+	var names []string
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name := filepath.Base(line)
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,48 @@
+package system
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseProcessNames(t *testing.T) {
+	output := `/sbin/launchd
+/usr/libexec/logd
+/Applications/zoom.us.app/Contents/MacOS/zoom.us
+/Applications/Microsoft Teams.app/Contents/MacOS/Microsoft Teams
+/usr/libexec/logd
+  bash
+`
+
+	want := []string{"launchd", "logd", "zoom.us", "Microsoft Teams", "bash"}
+	if got := ParseProcessNames(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseProcessNames() = %q, want %q", got, want)
+	}
+}
+
+func TestPsProcessAdapter_RunningProcesses(t *testing.T) {
+	var gotArgs []string
+	adapter := NewPsProcessAdapter(func(name string, args ...string) ([]byte, error) {
+		gotArgs = append([]string{name}, args...)
+		return []byte("/usr/bin/ssh\n"), nil
+	})
+
+	names, err := adapter.RunningProcesses()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"ssh"}) {
+		t.Errorf("Expected [ssh], got %q", names)
+	}
+	if gotArgs[0] != "ps" {
+		t.Errorf("Expected ps to be run, got %q", gotArgs)
+	}
+
+	failing := NewPsProcessAdapter(func(name string, args ...string) ([]byte, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := failing.RunningProcesses(); err == nil {
+		t.Error("Expected the runner error to be returned")
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/adapters/persistence"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
+)
+
+type fakeNetwork struct {
+	status   domain.Status
+	err      error
+	disabled int
+	enabled  int
+}
+
+func (f *fakeNetwork) CheckInterface(_ context.Context, name string) (domain.Status, error) {
+	return f.status, f.err
+}
+
+func (f *fakeNetwork) DescribeInterface(_ context.Context, name string) (domain.Interface, error) {
+	iface := domain.Interface{Name: name, Status: f.status, MTU: 1500, Ether: "6e:7e:67:aa:bb:cc"}
+	if f.status == domain.StatusUp {
+		iface.Flags = []string{"UP", "RUNNING"}
+	}
+	return iface, f.err
+}
+
+func (f *fakeNetwork) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	return []domain.Interface{
+		{Name: "lo0", Status: domain.StatusUp},
+		{Name: "en0", Status: domain.StatusUp},
+		{Name: "awdl0", Status: f.status},
+		{Name: "llw0", Status: domain.StatusUp},
+	}, nil
+}
+
+func (f *fakeNetwork) DisableInterface(_ context.Context, name string) error {
+	f.disabled++
+	f.status = domain.StatusDown
+	return nil
+}
+
+func (f *fakeNetwork) EnableInterface(_ context.Context, name string) error {
+	f.enabled++
+	f.status = domain.StatusUp
+	return nil
+}
+
+// fakeLog keeps logged events per day, standing in for the log files as
+// both the logger and the history
+type fakeLog struct {
+	days map[string][]domain.Event
+}
+
+func (f *fakeLog) Log(_ context.Context, event domain.Event) (domain.HistoryCursor, error) {
+	if f.days == nil {
+		f.days = make(map[string][]domain.Event)
+	}
+	key := event.Timestamp.Format("2006-01-02")
+	f.days[key] = append(f.days[key], event)
+
+	ts := event.Timestamp
+	day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location())
+	return domain.HistoryCursor{Day: day, Index: len(f.days[key]) - 1}, nil
+}
+
+func (f *fakeLog) Days() ([]time.Time, error) {
+	var days []time.Time
+	for key := range f.days {
+		day, _ := time.ParseInLocation("2006-01-02", key, time.Local)
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days, nil
+}
+
+func (f *fakeLog) ReadEvents(date time.Time) ([]domain.Event, error) {
+	return f.days[date.Format("2006-01-02")], nil
+}
+
+// newTestSession wires real services to in-memory fakes
+func newTestSession(events ...domain.Event) (*session, *fakeNetwork, *[]domain.Config) {
+	network := &fakeNetwork{status: domain.StatusDown}
+	repo := persistence.NewMemoryEventRepo()
+	log := &fakeLog{}
+	for _, e := range events {
+		repo.Add(context.Background(), e)
+		log.Log(context.Background(), e)
+	}
+
+	config := domain.DefaultConfig()
+	var saved []domain.Config
+
+	s := &session{
+		services: AppServices{
+			Monitor: services.NewMonitorService(network, log, repo, config),
+			Stats:   services.NewStatsService(repo),
+			History: services.NewHistoryService(log),
+			Config:  config.Clone(),
+			ConfigOnSave: func(_ context.Context, c *domain.Config) error {
+				saved = append(saved, *c)
+				return nil
+			},
+		},
+		monitoring:  true,
+		ctx:         context.Background(),
+		styles:      DefaultStyles(),
+		keys:        DefaultKeyMap(),
+		awdl0Status: domain.StatusUnknown,
+	}
+
+	return s, network, &saved
+}
+
+// refusingAllowList stands in for a helper that refuses every allow list
+type refusingAllowList struct{}
+
+func (refusingAllowList) AllowInterfaces(context.Context, []string) error {
+	return errors.New("rejected by helper")
+}
+
+// configure changes the config of both the TUI and the monitor, as saving
+// the settings does
+func configure(t *testing.T, s *session, change func(c *domain.Config)) {
+	t.Helper()
+	change(s.services.Config)
+	if _, err := s.services.Monitor.UpdateConfig(context.Background(), s.services.Config); err != nil {
+		t.Fatalf("Invalid config: %v", err)
+	}
+}
+
+func keyPress(k string) tea.KeyMsg {
+	switch k {
+	case "up":
+		return tea.KeyMsg{Type: tea.KeyUp}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "left":
+		return tea.KeyMsg{Type: tea.KeyLeft}
+	case "right":
+		return tea.KeyMsg{Type: tea.KeyRight}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "shift+tab":
+		return tea.KeyMsg{Type: tea.KeyShiftTab}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "pgup":
+		return tea.KeyMsg{Type: tea.KeyPgUp}
+	case "space":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// runCmd executes a command and everything it batches, returning the messages
+// settle runs cmd and lets the session take the configs the monitor
+// accepted, as the root model does. It returns every message, including those
+// of the saves.
+func settle(s *session, cmd tea.Cmd) []tea.Msg {
+	var msgs []tea.Msg
+	for _, msg := range runCmd(cmd) {
+		msgs = append(msgs, msg)
+		if applied, ok := msg.(configAppliedMsg); ok {
+			msgs = append(msgs, runCmd(s.configApplied(applied))...)
+		}
+	}
+	return msgs
+}
+
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	return []tea.Msg{msg}
+}
@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// Key binding scopes. Global bindings apply everywhere except while a
+// settings field is being edited or interfaces are being picked, when only
+// the bindings of that scope apply.
+const (
+	scopeGlobal       = "global"
+	scopeDashboard    = "dashboard"
+	scopeLogs         = "logs"
+	scopeStats        = "stats"
+	scopeTimeline     = "timeline"
+	scopeInterfaces   = "interfaces"
+	scopePicker       = "picker"
+	scopeSettings     = "settings"
+	scopeSettingsEdit = "settings-edit"
+)
+
+// KeyMap holds every key binding of the TUI
+type KeyMap struct {
+	Quit    key.Binding
+	Pause   key.Binding
+	NextTab key.Binding
+	PrevTab key.Binding
+	JumpTab key.Binding
+	Help    key.Binding
+	Elevate key.Binding
+
+	AllowShort key.Binding
+	AllowLong  key.Binding
+	EndAllow   key.Binding
+
+	Toggle key.Binding
+	Pick   key.Binding
+	Mark   key.Binding
+
+	Up       key.Binding
+	Down     key.Binding
+	Left     key.Binding
+	Right    key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Top      key.Binding
+	Bottom   key.Binding
+
+	Edit   key.Binding
+	Save   key.Binding
+	Cancel key.Binding
+	Reset  key.Binding
+	Apply  key.Binding
+	Revert key.Binding
+}
+
+// action names a binding in the config file and lists where it applies
+type action struct {
+	name    string
+	scopes  []string
+	binding *key.Binding
+}
+
+func (k *KeyMap) actions() []action {
+	return []action{
+		{"quit", []string{scopeGlobal}, &k.Quit},
+		{"pause", []string{scopeGlobal}, &k.Pause},
+		{"next_tab", []string{scopeGlobal}, &k.NextTab},
+		{"prev_tab", []string{scopeGlobal}, &k.PrevTab},
+		{"jump_tab", []string{scopeGlobal}, &k.JumpTab},
+		{"help", []string{scopeGlobal}, &k.Help},
+		{"elevate", []string{scopeGlobal}, &k.Elevate},
+		{"allow_short", []string{scopeGlobal}, &k.AllowShort},
+		{"allow_long", []string{scopeGlobal}, &k.AllowLong},
+		{"end_allow", []string{scopeGlobal}, &k.EndAllow},
+		{"toggle", []string{scopeDashboard, scopeInterfaces}, &k.Toggle},
+		{"pick", []string{scopeInterfaces}, &k.Pick},
+		{"mark", []string{scopePicker}, &k.Mark},
+		{"up", []string{scopeLogs, scopeSettings, scopePicker}, &k.Up},
+		{"down", []string{scopeLogs, scopeSettings, scopePicker}, &k.Down},
+		{"left", []string{scopeStats, scopeTimeline}, &k.Left},
+		{"right", []string{scopeStats, scopeTimeline}, &k.Right},
+		{"page_up", []string{scopeLogs}, &k.PageUp},
+		{"page_down", []string{scopeLogs}, &k.PageDown},
+		{"top", []string{scopeLogs}, &k.Top},
+		{"bottom", []string{scopeLogs}, &k.Bottom},
+		{"edit", []string{scopeSettings}, &k.Edit},
+		{"save", []string{scopeSettings}, &k.Save},
+		{"cancel", []string{scopeSettings}, &k.Cancel},
+		{"reset", []string{scopeSettings}, &k.Reset},
+		{"apply", []string{scopeSettingsEdit, scopePicker}, &k.Apply},
+		{"revert", []string{scopeSettingsEdit, scopePicker}, &k.Revert},
+	}
+}
+
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpLabel(keys), desc))
+}
+
+// DefaultKeyMap returns the built-in key bindings
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Quit:    newBinding("quit", "q", "ctrl+c"),
+		Pause:   newBinding("pause/resume", " "),
+		NextTab: newBinding("next tab", "tab"),
+		PrevTab: newBinding("previous tab", "shift+tab"),
+		JumpTab: newBinding("jump to tab", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		Help:    newBinding("toggle help", "?"),
+		Elevate: newBinding("enforce with sudo", "U"),
+
+		AllowShort: newBinding("allow briefly", "a"),
+		AllowLong:  newBinding("allow longer", "A"),
+		EndAllow:   newBinding("guard again", "x"),
+
+		Toggle: newBinding("toggle awdl0", "e", "E"),
+		Pick:   newBinding("pick interfaces", "i", "I"),
+		Mark:   newBinding("guard/unguard", " ", "x"),
+
+		Up:       newBinding("up", "up", "k"),
+		Down:     newBinding("down", "down", "j"),
+		Left:     newBinding("shorter window", "left", "h"),
+		Right:    newBinding("longer window", "right", "l"),
+		PageUp:   newBinding("page up", "pgup", "b"),
+		PageDown: newBinding("page down", "pgdown", "f"),
+		Top:      newBinding("oldest", "g", "home"),
+		Bottom:   newBinding("newest", "G", "end"),
+
+		Edit:   newBinding("edit", "enter"),
+		Save:   newBinding("save", "s", "S"),
+		Cancel: newBinding("cancel", "c", "C", "esc"),
+		Reset:  newBinding("reset to defaults", "r", "R"),
+		Apply:  newBinding("apply", "enter"),
+		Revert: newBinding("revert field", "esc"),
+	}
+}
+
+// NewKeyMap applies overrides from the config file, keyed by action name, to
+// the default bindings
+func NewKeyMap(overrides map[string][]string) (KeyMap, error) {
+	k := DefaultKeyMap()
+	if len(overrides) == 0 {
+		return k, nil
+	}
+
+	byName := make(map[string]*key.Binding)
+	var names []string
+	for _, a := range k.actions() {
+		byName[a.name] = a.binding
+		names = append(names, a.name)
+	}
+
+	var unknown []string
+	for name, keys := range overrides {
+		binding, ok := byName[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		if len(keys) == 0 {
+			return k, fmt.Errorf("key binding %q has no keys", name)
+		}
+
+		normalized := make([]string, len(keys))
+		for i, s := range keys {
+			normalized[i] = normalizeKey(s)
+		}
+		binding.SetKeys(normalized...)
+		binding.SetHelp(helpLabel(normalized), binding.Help().Desc)
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return k, fmt.Errorf("unknown key binding action(s) %s, expected one of %s",
+			strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+
+	return k, nil
+}
+
+// Conflicts lists every key that is bound to more than one action where
+// those actions can be triggered from the same tab
+func (k KeyMap) Conflicts() []string {
+	actions := k.actions()
+	scopes := []string{scopeDashboard, scopeLogs, scopeStats, scopeTimeline, scopeInterfaces, scopePicker, scopeSettings, scopeSettingsEdit}
+
+	seen := make(map[string]bool)
+	var conflicts []string
+
+	for _, scope := range scopes {
+		owners := make(map[string]string)
+
+		for _, a := range actions {
+			if !inScope(a, scope) {
+				continue
+			}
+
+			for _, keyName := range a.binding.Keys() {
+				other, taken := owners[keyName]
+				if !taken {
+					owners[keyName] = a.name
+					continue
+				}
+				if other == a.name {
+					continue
+				}
+
+				msg := fmt.Sprintf("key %q is bound to both %s and %s", displayKey(keyName), other, a.name)
+				if !seen[msg] {
+					seen[msg] = true
+					conflicts = append(conflicts, msg)
+				}
+			}
+		}
+	}
+
+	return conflicts
+}
+
+// inScope reports whether an action is reachable from the given scope. Global
+// actions are reachable from every scope except settings editing and picking.
+func inScope(a action, scope string) bool {
+	for _, s := range a.scopes {
+		if s == scope || (s == scopeGlobal && scope != scopeSettingsEdit && scope != scopePicker) {
+			return true
+		}
+	}
+	return false
+}
+
+// viewportKeyMap maps the scrolling bindings onto a viewport
+func (k KeyMap) viewportKeyMap() viewport.KeyMap {
+	disabled := key.NewBinding(key.WithDisabled())
+
+	return viewport.KeyMap{
+		Up:           k.Up,
+		Down:         k.Down,
+		PageUp:       k.PageUp,
+		PageDown:     k.PageDown,
+		HalfPageUp:   disabled,
+		HalfPageDown: disabled,
+		Left:         disabled,
+		Right:        disabled,
+	}
+}
+
+// jumpIndex returns the tab a jump key points at
+func (k KeyMap) jumpIndex(keyName string) (int, bool) {
+	for i, s := range k.JumpTab.Keys() {
+		if s == keyName {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// contextKeyMap is the help.KeyMap for the active tab
+type contextKeyMap struct {
+	global []key.Binding
+	tab    []key.Binding
+}
+
+func (c contextKeyMap) ShortHelp() []key.Binding {
+	return append(append([]key.Binding{}, c.tab...), c.global...)
+}
+
+func (c contextKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{c.tab, c.global}
+}
+
+func normalizeKey(s string) string {
+	if strings.EqualFold(s, "space") {
+		return " "
+	}
+	return s
+}
+
+func displayKey(s string) string {
+	switch s {
+	case " ":
+		return "space"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	}
+	return s
+}
+
+func helpLabel(keys []string) string {
+	if len(keys) > 3 {
+		return displayKey(keys[0]) + "-" + displayKey(keys[len(keys)-1])
+	}
+
+	labels := make([]string, len(keys))
+	for i, s := range keys {
+		labels[i] = displayKey(s)
+	}
+	return strings.Join(labels, "/")
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestDefaultKeyMap_HasNoConflicts(t *testing.T) {
+	if conflicts := DefaultKeyMap().Conflicts(); len(conflicts) > 0 {
+		t.Errorf("Expected no conflicts in the defaults, got %v", conflicts)
+	}
+}
+
+func TestNewKeyMap_Overrides(t *testing.T) {
+	keys, err := NewKeyMap(map[string][]string{
+		"pause": {"p", "space"},
+		"quit":  {"x"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !key.Matches(keyPress("p"), keys.Pause) || !key.Matches(keyPress("space"), keys.Pause) {
+		t.Error("Expected pause to be bound to p and space")
+	}
+	if key.Matches(keyPress("q"), keys.Quit) {
+		t.Error("Expected q to no longer quit")
+	}
+	if got := keys.Pause.Help().Key; got != "p/space" {
+		t.Errorf("Expected the help label to follow the override, got %q", got)
+	}
+}
+
+func TestNewKeyMap_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		want      string
+	}{
+		{"Unknown Action", map[string][]string{"explode": {"x"}}, "explode"},
+		{"No Keys", map[string][]string{"quit": {}}, "no keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyMap(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected an error mentioning %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestKeyMap_Conflicts(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		conflicts int
+	}{
+		{"Global Against Tab", map[string][]string{"pause": {"e"}}, 1},
+		{"Within Tab", map[string][]string{"save": {"r"}}, 1},
+		{"Different Tabs", map[string][]string{"left": {"s"}}, 0},
+		{"Edit Mode Ignores Globals", map[string][]string{"revert": {"q"}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NewKeyMap(tt.overrides)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := keys.Conflicts(); len(got) != tt.conflicts {
+				t.Errorf("Expected %d conflicts, got %v", tt.conflicts, got)
+			}
+		})
+	}
+}
+
+func TestModel_HelpOverlay(t *testing.T) {
+	m := newTestModel()
+
+	if contains(m.View(), "toggle help") && contains(m.View(), "Dashboard keys") {
+		t.Fatal("Expected the full help to be hidden initially")
+	}
+
+	updated, _ := m.Update(keyPress("?"))
+	m = updated.(Model)
+
+	if !m.help.ShowAll || !contains(m.View(), "Dashboard keys") {
+		t.Error("Expected ? to show the full help")
+	}
+	if !contains(m.View(), "toggle awdl0") {
+		t.Error("Expected the full help to list the tab's bindings")
+	}
+}
@@ -0,0 +1,102 @@
+package ui
+
+import "github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+
+const (
+	// logChunkSize is how many events are loaded from history at a time
+	logChunkSize = 100
+	// maxLogEntries bounds how many events the log view keeps in memory
+	maxLogEntries = 500
+)
+
+// logWindow is the slice of history currently held by the log view. It grows
+// backward or forward a chunk at a time and drops entries from the opposite
+// end once it exceeds maxLogEntries.
+type logWindow struct {
+	entries []domain.HistoryEntry
+
+	// following is true while the window ends at the newest logged event,
+	// so live events can be appended directly
+	following bool
+	// atStart is true once there is nothing older left to load
+	atStart bool
+}
+
+func newLogWindow(latest []domain.HistoryEntry) logWindow {
+	return logWindow{
+		entries:   latest,
+		following: true,
+		atStart:   len(latest) < logChunkSize,
+	}
+}
+
+// prepend adds older entries and returns how many were added
+func (w *logWindow) prepend(older []domain.HistoryEntry) int {
+	if len(older) < logChunkSize {
+		w.atStart = true
+	}
+
+	w.entries = append(append([]domain.HistoryEntry{}, older...), w.entries...)
+
+	if excess := len(w.entries) - maxLogEntries; excess > 0 {
+		w.entries = w.entries[:maxLogEntries]
+		w.following = false
+	}
+
+	return len(older)
+}
+
+// appendNewer adds entries loaded from history after the window and returns
+// how many entries were dropped from the front
+func (w *logWindow) appendNewer(newer []domain.HistoryEntry) int {
+	if len(newer) < logChunkSize {
+		w.following = true
+	}
+
+	w.entries = append(w.entries, newer...)
+
+	return w.trimFront()
+}
+
+// appendLive adds an event that was just logged and returns how many entries
+// were dropped from the front. Live events are ignored while the window is
+// scrolled back in history; they are picked up again by appendNewer. Events
+// that could not be logged have no place in the history and are skipped.
+func (w *logWindow) appendLive(evt domain.Event) int {
+	if !w.following || evt.Cursor.Day.IsZero() {
+		return 0
+	}
+
+	w.entries = append(w.entries, domain.HistoryEntry{
+		Cursor: evt.Cursor,
+		Event:  evt,
+	})
+
+	return w.trimFront()
+}
+
+func (w *logWindow) trimFront() int {
+	excess := len(w.entries) - maxLogEntries
+	if excess <= 0 {
+		return 0
+	}
+
+	w.entries = w.entries[excess:]
+	w.atStart = false
+
+	return excess
+}
+
+func (w *logWindow) first() (domain.HistoryCursor, bool) {
+	if len(w.entries) == 0 {
+		return domain.HistoryCursor{}, false
+	}
+	return w.entries[0].Cursor, true
+}
+
+func (w *logWindow) last() (domain.HistoryCursor, bool) {
+	if len(w.entries) == 0 {
+		return domain.HistoryCursor{}, false
+	}
+	return w.entries[len(w.entries)-1].Cursor, true
+}
@@ -1,70 +1,145 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
-
-	"github.com/charmbracelet/bubbles/viewport"
 )
 
 type AppServices struct {
-	Monitor      *services.MonitorService
-	Stats        *services.StatsService
+	Monitor *services.MonitorService
+	Stats   *services.StatsService
+	History *services.HistoryService
+	// Config is the TUI's own copy of the configuration. Changes reach the
+	// monitor through UpdateConfig and SetInterfaces.
 	Config       *domain.Config
-	ConfigOnSave func(*domain.Config) error
+	ConfigOnSave func(context.Context, *domain.Config) error
+	// Context bounds the work the TUI starts; cancelling it stops whatever
+	// is still in flight. Nil means context.Background().
+	Context context.Context
 }
 
-type Model struct {
+// session holds the state shared by the header and every tab
+type session struct {
 	services   AppServices
 	monitoring bool
-	showLogs   bool
-	logBuffer  []domain.Event
-	viewport   viewport.Model
-	buckets    []domain.Bucket
+	// tickGen numbers the chain of ticks. Resuming starts a new chain, and
+	// ticks of an older one are dropped.
+	tickGen int
+	// ctx is passed to every service call
+	ctx context.Context
+
+	// Styles
+	appearance Appearance
+	styles     Styles
+	keys       KeyMap
+
+	// awdl0 status
+	awdl0Status domain.Status
+	// checkErr is the error of the last check, if it failed
+	checkErr error
+	// lastWouldDisable is the latest disable skipped in observe-only mode
+	lastWouldDisable *domain.Event
+	// helperDown is set once a change failed because the privileged helper
+	// is gone, until a change goes through again
+	helperDown bool
+
+	// checking is set while a check runs. Only one check runs at a time: the
+	// first tick that fires meanwhile is delayed until it finishes, further
+	// ones are skipped.
+	checking     bool
+	checkPending bool
+	skippedTicks int
+	delayedTicks int
+
+	// describing is set while the details of the guarded interfaces are
+	// queried for the Interfaces tab. A request meanwhile is delayed until the
+	// query finishes, further ones are skipped.
+	describing      bool
+	describePending bool
+
+	// Last hour histogram
+	buckets []domain.Bucket
+}
+
+// tab is a screen of the TUI. Tabs receive every non-key message, but only
+// the active tab receives key presses.
+type tab interface {
+	Title() string
+	Update(msg tea.Msg) (tab, tea.Cmd)
+	View(width, height int) string
+	// KeyBindings lists the bindings the tab currently responds to
+	KeyBindings() []key.Binding
+}
+
+// inputCapturer is implemented by tabs that sometimes need every key press,
+// such as while a text field is being edited
+type inputCapturer interface {
+	capturesInput() bool
+}
+
+type Model struct {
+	session *session
+	tabs    []tab
+	active  int
+	help    help.Model
 
 	// Status Message
 	statusMsg string
 
+	// elevate asks main to restart under sudo once the TUI has quit
+	elevate bool
+
 	// Terminal Dimensions
 	width, height int
-
-	// Styles
-	styles Styles
-
-	// awdl0 status
-	awdl0Status domain.Status
 }
 
 func NewModel(services AppServices) Model {
-	m := Model{
+	// Invalid overrides are reported by main before the TUI starts
+	keys, err := NewKeyMap(services.Config.KeyBindings)
+	if err != nil {
+		keys = DefaultKeyMap()
+	}
+
+	ctx := services.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	s := &session{
 		services:    services,
 		monitoring:  true,
-		logBuffer:   []domain.Event{},
-		viewport:    viewport.New(0, 0),
-		styles:      DefaultStyles(),
+		ctx:         ctx,
+		appearance:  Appearance{DarkBackground: true},
+		keys:        keys,
 		awdl0Status: domain.StatusUnknown,
 	}
+	s.refreshStyles()
 
-	// Load historical logs (last 24 hours)
-	recentEvents := services.Stats.GetRecentEvents(24 * time.Hour)
-	for _, e := range recentEvents {
-		m.logBuffer = append(m.logBuffer, e)
-	}
+	m := Model{session: s, help: help.New()}
 
-	// Trim buffer to max size
-	if len(m.logBuffer) > 100 {
-		m.logBuffer = m.logBuffer[:100]
+	// Load the most recent chunk of history; older chunks are loaded on demand
+	latest, err := services.History.Latest(logChunkSize)
+	if err != nil {
+		m.statusMsg = fmt.Sprintf("Error loading logs: %v", err)
 	}
 
-	// Initialize viewport content
-	m.viewport.SetContent(m.renderLogs())
-	m.viewport.GotoBottom()
+	m.tabs = []tab{
+		newDashboardTab(s),
+		newLogsTab(s, latest),
+		newStatsTab(s),
+		newTimelineTab(s),
+		newInterfacesTab(s),
+		newSettingsTab(s),
+	}
 
 	return m
 }
@@ -72,58 +147,207 @@ func NewModel(services AppServices) Model {
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		tea.EnterAltScreen,
-		m.tickCmd(),
+		m.session.tickCmd(),
 	)
 }
 
 // Messages
-type tickMsg time.Time
+
+// tickMsg fires a poll of the tick chain gen
+type tickMsg struct {
+	gen int
+}
 
 type checkResultMsg struct {
-	Event *domain.Event
-	Err   error
+	Events []domain.Event
+	Err    error
 }
 
-type toggleMsg struct {
-	Event *domain.Event
-	Err   error
+// actionMsg is the result of a user action such as toggling or snoozing
+type actionMsg struct {
+	Action string
+	Events []domain.Event
+	Err    error
+}
+
+// detailsMsg carries the live details of every guarded interface
+type detailsMsg struct {
+	Interfaces []domain.Interface
+	// Errs holds the error of each interface that could not be described
+	Errs map[string]error
+}
+
+// interfaceListMsg carries every interface of the system
+type interfaceListMsg struct {
+	Interfaces []domain.Interface
+	Err        error
+}
+
+type historyLoadedMsg struct {
+	Entries []domain.HistoryEntry
+	Older   bool
+	Err     error
+}
+
+// configAppliedMsg is the result of handing a new config to the monitor. The
+// session only takes Config once the monitor has.
+type configAppliedMsg struct {
+	Action string
+	Config *domain.Config
+	Events []domain.Event
+	Err    error
 }
 
 type configSavedMsg struct {
 	Err error
 }
 
+// notifyMsg asks the root model to show a transient message in the footer
+type notifyMsg string
+
 type clearStatusMsg struct{}
 
 // Commands
-func (m Model) tickCmd() tea.Cmd {
-	if !m.monitoring {
+func (s *session) tickCmd() tea.Cmd {
+	if !s.monitoring {
 		return nil
 	}
-	// Use the current config interval
-	return tea.Tick(m.services.Config.PollingInterval, func(t time.Time) tea.Msg {
-		return tickMsg(t)
+	// The monitor may poll faster than configured while an interface flaps
+	gen := s.tickGen
+	return tea.Tick(s.services.Monitor.NextPoll(), func(time.Time) tea.Msg {
+		return tickMsg{gen: gen}
 	})
 }
 
-func (m Model) checkNetworkCmd() tea.Cmd {
+// startCheck runs a check unless one is still in flight
+func (s *session) startCheck() tea.Cmd {
+	switch {
+	case !s.checking:
+		s.checking = true
+		return s.checkNetworkCmd()
+	case !s.checkPending:
+		s.checkPending = true
+		s.delayedTicks++
+	default:
+		s.skippedTicks++
+	}
+	return nil
+}
+
+// startDescribe queries the interface details unless a query is still in
+// flight
+func (s *session) startDescribe() tea.Cmd {
+	if s.describing {
+		s.describePending = true
+		return nil
+	}
+	s.describing = true
+	return s.describeCmd()
+}
+
+func (s *session) checkNetworkCmd() tea.Cmd {
+	return func() tea.Msg {
+		events, err := s.services.Monitor.Tick(s.ctx)
+		return checkResultMsg{Events: events, Err: err}
+	}
+}
+
+func (s *session) toggleInterfaceCmd() tea.Cmd {
+	name := s.primaryInterface()
+	return func() tea.Msg {
+		event, err := s.services.Monitor.ToggleInterface(s.ctx, name)
+
+		return actionMsg{Action: "toggling", Events: eventList(event), Err: err}
+	}
+}
+
+// allowCmd snoozes every guarded interface for the given duration
+func (s *session) allowCmd(d time.Duration) tea.Cmd {
+	names := append([]string{}, s.services.Config.Interfaces...)
+	return func() tea.Msg {
+		var events []domain.Event
+		for _, name := range names {
+			event, err := s.services.Monitor.Allow(s.ctx, name, d)
+			if err != nil {
+				return actionMsg{Action: "allowing", Events: events, Err: err}
+			}
+			events = append(events, eventList(event)...)
+		}
+		return actionMsg{Action: "allowing", Events: events}
+	}
+}
+
+// endSnoozeCmd resumes guarding every snoozed interface
+func (s *session) endSnoozeCmd() tea.Cmd {
+	return func() tea.Msg {
+		var events []domain.Event
+		for _, snooze := range s.services.Monitor.Snoozes() {
+			event, err := s.services.Monitor.EndSnooze(s.ctx, snooze.Interface)
+			if err != nil {
+				return actionMsg{Action: "ending snooze", Events: events, Err: err}
+			}
+			events = append(events, eventList(event)...)
+		}
+		return actionMsg{Action: "ending snooze", Events: events}
+	}
+}
+
+// describeCmd queries the details of every guarded interface
+func (s *session) describeCmd() tea.Cmd {
+	names := append([]string{}, s.services.Config.Interfaces...)
+	return func() tea.Msg {
+		msg := detailsMsg{Errs: make(map[string]error)}
+		for _, name := range names {
+			iface, err := s.services.Monitor.Describe(s.ctx, name)
+			if err != nil {
+				msg.Errs[name] = err
+			}
+			msg.Interfaces = append(msg.Interfaces, iface)
+		}
+		return msg
+	}
+}
+
+// listInterfacesCmd lists every interface of the system
+func (s *session) listInterfacesCmd() tea.Cmd {
+	return func() tea.Msg {
+		ifaces, err := s.services.Monitor.ListInterfaces(s.ctx)
+		return interfaceListMsg{Interfaces: ifaces, Err: err}
+	}
+}
+
+// setInterfacesCmd replaces the guarded interfaces. The config is saved once
+// the monitor guards them.
+func (s *session) setInterfacesCmd(names []string) tea.Cmd {
+	config := s.services.Config.Clone()
+	config.Interfaces = append([]string{}, names...)
 	return func() tea.Msg {
-		event, err := m.services.Monitor.Tick()
-		return checkResultMsg{Event: event, Err: err}
+		events, err := s.services.Monitor.SetInterfaces(s.ctx, names)
+		return configAppliedMsg{Action: "guarding", Config: config, Events: events, Err: err}
 	}
 }
 
-func (m Model) toggleInterfaceCmd() tea.Cmd {
+// updateConfigCmd hands the edited config to the monitor. It is saved once
+// the monitor runs with it.
+func (s *session) updateConfigCmd(config *domain.Config) tea.Cmd {
 	return func() tea.Msg {
-		event, err := m.services.Monitor.ToggleInterface()
+		events, err := s.services.Monitor.UpdateConfig(s.ctx, config)
+		return configAppliedMsg{Action: "applying settings", Config: config, Events: events, Err: err}
+	}
+}
 
-		return toggleMsg{Event: event, Err: err}
+func eventList(evt *domain.Event) []domain.Event {
+	if evt == nil {
+		return nil
 	}
+	return []domain.Event{*evt}
 }
 
-func (m Model) saveConfigCmd() tea.Cmd {
+// saveConfigCmd saves the config as it is now
+func (s *session) saveConfigCmd() tea.Cmd {
+	config := s.services.Config.Clone()
 	return func() tea.Msg {
-		err := m.services.ConfigOnSave(m.services.Config)
+		err := s.services.ConfigOnSave(s.ctx, config)
 		return configSavedMsg{Err: err}
 	}
 }
@@ -139,103 +363,128 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		keys := m.session.keys
+
+		// ctrl+c always quits, even while a tab captures input
+		if c, ok := m.tabs[m.active].(inputCapturer); ok && c.capturesInput() && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.tabs[m.active], cmd = m.tabs[m.active].Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit), msg.String() == "ctrl+c":
 			// Cleanup is handled in main.go after p.Run() returns
 			return m, tea.Quit
 
-		case " ":
-			m.monitoring = !m.monitoring
-			if m.monitoring {
-				cmds = append(cmds, m.tickCmd())
+		case key.Matches(msg, keys.Help):
+			m.help.ShowAll = !m.help.ShowAll
+
+		case key.Matches(msg, keys.Elevate) && m.session.services.Monitor.ObserveOnly():
+			m.elevate = true
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Pause):
+			m.session.monitoring = !m.session.monitoring
+			if m.session.monitoring {
+				m.session.tickGen++
+				cmds = append(cmds, m.session.tickCmd())
 			}
 
-		case "l", "L":
-			m.showLogs = !m.showLogs
-
-		case "e", "E":
-			cmds = append(cmds, m.toggleInterfaceCmd())
-
-		case "up", "right", "down", "left":
-			// If logs are shown, these keys are for scrolling the viewport, not changing polling
-			if !m.showLogs {
-				oldInterval := m.services.Config.PollingInterval
-				if msg.String() == "up" || msg.String() == "right" {
-					m.services.Config.PollingInterval += 100 * time.Millisecond
-				} else { // "down" or "left"
-					m.services.Config.PollingInterval -= 100 * time.Millisecond
-				}
-				m.services.Config.Clamp()
-				if oldInterval != m.services.Config.PollingInterval {
-					cmds = append(cmds, m.saveConfigCmd())
-				}
+		case key.Matches(msg, keys.NextTab):
+			m.active = (m.active + 1) % len(m.tabs)
+			cmds = append(cmds, m.describeIfShown())
+
+		case key.Matches(msg, keys.PrevTab):
+			m.active = (m.active + len(m.tabs) - 1) % len(m.tabs)
+			cmds = append(cmds, m.describeIfShown())
+
+		case key.Matches(msg, keys.JumpTab):
+			if i, ok := keys.jumpIndex(msg.String()); ok && i < len(m.tabs) {
+				m.active = i
+				cmds = append(cmds, m.describeIfShown())
 			}
+
+		case key.Matches(msg, keys.AllowShort):
+			cmds = append(cmds, m.session.allowCmd(m.session.services.Config.SnoozeShort))
+
+		case key.Matches(msg, keys.AllowLong):
+			cmds = append(cmds, m.session.allowCmd(m.session.services.Config.SnoozeLong))
+
+		case key.Matches(msg, keys.EndAllow):
+			cmds = append(cmds, m.session.endSnoozeCmd())
+
+		default:
+			var cmd tea.Cmd
+			m.tabs[m.active], cmd = m.tabs[m.active].Update(msg)
+			cmds = append(cmds, cmd)
 		}
 
+		return m, tea.Batch(cmds...)
+
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.viewport.Width = msg.Width
-		m.viewport.Height = msg.Height - 6 // Standardize on dynamic calculation in View()
+		m.help.Width = msg.Width
+
+		// Tabs only get the area between the header and the footer
+		return m, m.broadcast(tea.WindowSizeMsg{Width: m.width, Height: m.contentHeight()})
 
 	case tickMsg:
-		if m.monitoring {
+		if m.session.monitoring && msg.gen == m.session.tickGen {
 			// Trigger next tick
-			cmds = append(cmds, m.tickCmd())
+			cmds = append(cmds, m.session.tickCmd())
 			// Trigger network check
-			cmds = append(cmds, m.checkNetworkCmd())
+			cmds = append(cmds, m.session.startCheck())
 		}
 
 	case checkResultMsg:
-		// Handle result of network check
-		if msg.Event == nil {
-			// No event, just update stats
-			m.buckets = m.services.Stats.GetHistogram(1*time.Hour, 60)
-			return m, nil
-		}
-
-		// Event occurred
-		m.logBuffer = append(m.logBuffer, *msg.Event)
-		// Trim buffer
-		if len(m.logBuffer) > 100 {
-			m.logBuffer = m.logBuffer[len(m.logBuffer)-100:]
+		m.session.checking = false
+		if m.session.checkPending {
+			m.session.checkPending = false
+			if m.session.monitoring {
+				cmds = append(cmds, m.session.startCheck())
+			}
 		}
-		m.viewport.SetContent(m.renderLogs())
-		m.viewport.GotoBottom()
 
 		// Update stats after check
-		m.buckets = m.services.Stats.GetHistogram(1*time.Hour, 60)
+		m.session.buckets = m.session.services.Stats.GetHistogram(m.session.ctx, 1*time.Hour, 60)
 
-		// Update awdl0 status
-		if msg.Event.Type == domain.EventDisable {
-			m.awdl0Status = domain.StatusDown
-		} else if msg.Event.Type == domain.EventEnable {
-			m.awdl0Status = domain.StatusUp
-		}
+		m.session.applyEvents(msg.Events)
+		m.session.checkErr = msg.Err
+		m.session.trackHelper(msg.Events, msg.Err)
+		cmds = append(cmds, m.describeIfShown())
+
+	case actionMsg:
+		m.session.applyEvents(msg.Events)
+		m.session.trackHelper(msg.Events, msg.Err)
 
-	case toggleMsg:
 		if msg.Err != nil {
-			m.statusMsg = fmt.Sprintf("Error toggling: %v", msg.Err)
+			m.statusMsg = fmt.Sprintf("Error %s: %v", msg.Action, msg.Err)
 			cmds = append(cmds, clearStatusCmd())
-			return m, tea.Batch(cmds...)
 		}
 
-		if msg.Event != nil {
-			m.logBuffer = append(m.logBuffer, *msg.Event)
-			if len(m.logBuffer) > 100 {
-				m.logBuffer = m.logBuffer[len(m.logBuffer)-100:]
-			}
-			m.viewport.SetContent(m.renderLogs())
-			m.viewport.GotoBottom()
-			if msg.Event.Type == domain.EventDisable {
-				m.awdl0Status = domain.StatusDown
-			} else if msg.Event.Type == domain.EventEnable {
-				m.awdl0Status = domain.StatusUp
-			}
+		// Update stats after check
+		m.session.buckets = m.session.services.Stats.GetHistogram(m.session.ctx, 1*time.Hour, 60)
+		cmds = append(cmds, m.describeIfShown())
+
+	case detailsMsg:
+		m.session.describing = false
+		if m.session.describePending {
+			m.session.describePending = false
+			cmds = append(cmds, m.describeIfShown())
 		}
 
-		// Update stats after check
-		m.buckets = m.services.Stats.GetHistogram(1*time.Hour, 60)
+	case historyLoadedMsg:
+		if msg.Err != nil {
+			m.statusMsg = fmt.Sprintf("Error loading logs: %v", msg.Err)
+			cmds = append(cmds, clearStatusCmd())
+		}
+
+	case configAppliedMsg:
+		cmds = append(cmds, m.session.configApplied(msg))
+		updated, cmd := m.Update(actionMsg{Action: msg.Action, Events: msg.Events, Err: msg.Err})
+		return updated, tea.Batch(append(cmds, cmd)...)
 
 	case configSavedMsg:
 		if msg.Err != nil {
@@ -245,25 +494,131 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, clearStatusCmd())
 
+	case notifyMsg:
+		m.statusMsg = string(msg)
+		cmds = append(cmds, clearStatusCmd())
+
 	case clearStatusMsg:
 		m.statusMsg = ""
 	}
 
-	// Handle viewport updates if logs are shown
-	if m.showLogs {
-		var vpCmd tea.Cmd
-		m.viewport, vpCmd = m.viewport.Update(msg)
-		cmds = append(cmds, vpCmd)
-	}
+	cmds = append(cmds, m.broadcast(msg))
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m Model) renderLogs() string {
-	var content strings.Builder
-	for _, event := range m.logBuffer {
-		timestamp := m.styles.Timestamp.Render(event.Timestamp.Format("15:04:05"))
-		content.WriteString(fmt.Sprintf("%s %s\n", timestamp, event.Message))
+// describeIfShown refreshes the interface details while the Interfaces tab is
+// shown. Hidden, nobody looks at them, so they are not queried.
+func (m Model) describeIfShown() tea.Cmd {
+	if _, ok := m.tabs[m.active].(interfacesTab); !ok {
+		return nil
+	}
+	return m.session.startDescribe()
+}
+
+// ElevateRequested reports whether the user quit an observe-only monitor to
+// restart it under sudo
+func (m Model) ElevateRequested() bool {
+	return m.elevate
+}
+
+// WithAppearance adapts the styles to the terminal the TUI runs in
+func (m Model) WithAppearance(a Appearance) Model {
+	m.session.appearance = a
+	m.session.refreshStyles()
+	return m
+}
+
+// configApplied makes the config the monitor took that of the session, with
+// its keys and styles, and saves it. A config the monitor refused changes
+// nothing.
+func (s *session) configApplied(msg configAppliedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return nil
+	}
+
+	*s.services.Config = *msg.Config.Clone()
+	// Invalid bindings were rejected before the config was applied
+	if keys, err := NewKeyMap(msg.Config.KeyBindings); err == nil {
+		s.keys = keys
+	}
+	s.refreshStyles()
+	return s.saveConfigCmd()
+}
+
+// refreshStyles rebuilds the styles from the config. Invalid overrides are
+// reported by main before the TUI starts, so they fall back to the defaults.
+func (s *session) refreshStyles() {
+	s.styles, _ = NewStyles(s.services.Config, s.appearance)
+}
+
+// keyMap returns the bindings shown in the help for the active tab
+func (m Model) keyMap() contextKeyMap {
+	keys := m.session.keys
+	global := []key.Binding{keys.NextTab, keys.JumpTab, keys.Pause, keys.Help, keys.Quit}
+	if m.session.services.Monitor.ObserveOnly() {
+		global = append([]key.Binding{keys.Elevate}, global...)
+	}
+
+	if c, ok := m.tabs[m.active].(inputCapturer); ok && c.capturesInput() {
+		global = nil
+	}
+
+	return contextKeyMap{global: global, tab: m.tabs[m.active].KeyBindings()}
+}
+
+// broadcast forwards a non-key message to every tab
+func (m *Model) broadcast(msg tea.Msg) tea.Cmd {
+	var cmds []tea.Cmd
+	for i := range m.tabs {
+		var cmd tea.Cmd
+		m.tabs[i], cmd = m.tabs[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// primaryInterface is the guarded interface shown in the header
+func (s *session) primaryInterface() string {
+	if len(s.services.Config.Interfaces) == 0 {
+		return "awdl0"
+	}
+	return s.services.Config.Interfaces[0]
+}
+
+// trackHelper notes whether the privileged helper failed a change, or
+// carried one out
+func (s *session) trackHelper(events []domain.Event, err error) {
+	if errors.Is(err, domain.ErrHelperUnavailable) {
+		s.helperDown = true
+		return
+	}
+	for _, evt := range events {
+		if evt.Type == domain.EventDisable || evt.Type == domain.EventEnable {
+			s.helperDown = false
+		}
+	}
+}
+
+// applyEvents updates the shared status of the primary interface
+func (s *session) applyEvents(events []domain.Event) {
+	for _, evt := range events {
+		if evt.Type == domain.EventWouldDisable {
+			s.lastWouldDisable = &evt
+		}
+		if evt.Interface != "" && evt.Interface != s.primaryInterface() {
+			continue
+		}
+
+		switch evt.Type {
+		case domain.EventCheck:
+			if evt.Status != "" {
+				s.awdl0Status = evt.Status
+			}
+		case domain.EventDisable:
+			s.awdl0Status = domain.StatusDown
+		case domain.EventEnable, domain.EventSnoozeStart:
+			s.awdl0Status = domain.StatusUp
+		}
 	}
-	return content.String()
 }
@@ -0,0 +1,391 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func newTestModel() Model {
+	s, _, _ := newTestSession()
+	m := NewModel(s.services)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	return updated.(Model)
+}
+
+func TestModel_TabNavigation(t *testing.T) {
+	m := newTestModel()
+
+	tests := []struct {
+		key  string
+		want int
+	}{
+		{"tab", 1},
+		{"tab", 2},
+		{"shift+tab", 1},
+		{"6", 5},
+		{"tab", 0},
+		{"shift+tab", 5},
+		{"9", 5},
+		{"1", 0},
+	}
+
+	for _, tt := range tests {
+		updated, _ := m.Update(keyPress(tt.key))
+		m = updated.(Model)
+		if m.active != tt.want {
+			t.Errorf("After %q expected tab %d, got %d", tt.key, tt.want, m.active)
+		}
+	}
+}
+
+func TestModel_KeysOnlyReachActiveTab(t *testing.T) {
+	m := newTestModel()
+
+	// "e" toggles awdl0 on the dashboard
+	if _, cmd := m.Update(keyPress("e")); cmd == nil {
+		t.Error("Expected the dashboard to handle 'e'")
+	}
+
+	// but means nothing on the stats tab
+	updated, _ := m.Update(keyPress("3"))
+	m = updated.(Model)
+	if _, cmd := m.Update(keyPress("e")); len(runCmd(cmd)) != 0 {
+		t.Error("Expected the stats tab to ignore 'e'")
+	}
+}
+
+func TestModel_PauseIsGlobal(t *testing.T) {
+	m := newTestModel()
+
+	updated, _ := m.Update(keyPress("2"))
+	m = updated.(Model)
+	updated, _ = m.Update(keyPress("space"))
+	m = updated.(Model)
+
+	if m.session.monitoring {
+		t.Error("Expected space to pause monitoring from any tab")
+	}
+}
+
+func TestModel_Quit(t *testing.T) {
+	m := newTestModel()
+
+	_, cmd := m.Update(keyPress("q"))
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("Expected 'q' to quit")
+	}
+}
+
+func TestModel_ViewShowsTabBar(t *testing.T) {
+	m := newTestModel()
+
+	view := m.View()
+	for _, title := range []string{"Dashboard", "Logs", "Stats", "Timeline", "Interfaces", "Settings"} {
+		if !contains(view, title) {
+			t.Errorf("Expected tab bar to contain %q", title)
+		}
+	}
+}
+
+func TestModel_AllowShowsCountdown(t *testing.T) {
+	m := newTestModel()
+
+	_, cmd := m.Update(keyPress("a"))
+	for _, msg := range runCmd(cmd) {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	if !contains(m.View(), "ALLOWED awdl0 ") {
+		t.Errorf("Expected a snooze countdown in the header, got:\n%s", m.renderHeader())
+	}
+
+	_, cmd = m.Update(keyPress("x"))
+	for _, msg := range runCmd(cmd) {
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	if contains(m.View(), "ALLOWED") {
+		t.Error("Expected the countdown to disappear once guarding resumes")
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "0:00"},
+		{65 * time.Second, "1:05"},
+		{90 * time.Minute, "1:30:00"},
+	}
+
+	for _, tt := range tests {
+		if got := formatCountdown(tt.d); got != tt.want {
+			t.Errorf("formatCountdown(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestModel_HeaderShowsSchedule(t *testing.T) {
+	m := newTestModel()
+	configure(t, m.session, func(c *domain.Config) {
+		c.Schedule = &domain.Schedule{
+			Default: domain.ModeObserve,
+			Rules: []domain.ScheduleRule{
+				{Mode: domain.ModeEnforce, Days: []string{"mon-sun"}, Start: "00:00", End: "00:00"},
+			},
+		}
+	})
+
+	if header := m.renderHeader(); !contains(header, "ENFORCE mon-sun 00:00-00:00") {
+		t.Errorf("Expected the active schedule in the header, got:\n%s", header)
+	}
+}
+
+func TestModel_HeaderShowsFlapping(t *testing.T) {
+	s, network, _ := newTestSession()
+	configure(t, s, func(c *domain.Config) { c.FlapThreshold = 1 })
+	network.status = domain.StatusUp
+
+	m := NewModel(s.services)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+
+	for i := 0; i < 2; i++ {
+		network.status = domain.StatusUp
+		if _, err := s.services.Monitor.Tick(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if header := m.renderHeader(); !contains(header, "FLAPPING awdl0") {
+		t.Errorf("Expected a flapping badge in the header, got:\n%s", header)
+	}
+}
+
+func TestModel_HelperDown(t *testing.T) {
+	m := newTestModel()
+
+	gone := fmt.Errorf("disabling awdl0: helper: exited: %w", domain.ErrHelperUnavailable)
+	updated, _ := m.Update(checkResultMsg{Err: errors.Join(errors.New("other"), gone)})
+	m = updated.(Model)
+	if header := m.renderHeader(); !contains(header, "HELPER DOWN") {
+		t.Errorf("Expected the helper to be reported down, got:\n%s", header)
+	}
+
+	// A tick that changed nothing says nothing about the helper
+	updated, _ = m.Update(checkResultMsg{})
+	m = updated.(Model)
+	if !m.session.helperDown {
+		t.Error("Expected the helper to stay down")
+	}
+
+	updated, _ = m.Update(actionMsg{Action: "toggling", Events: []domain.Event{{Type: domain.EventEnable, Interface: "awdl0"}}})
+	m = updated.(Model)
+	if header := m.renderHeader(); contains(header, "HELPER DOWN") {
+		t.Errorf("Expected the helper to be back once a change went through, got:\n%s", header)
+	}
+}
+
+func TestModel_HeaderShowsEffectiveInterval(t *testing.T) {
+	m := newTestModel()
+
+	if header := m.renderHeader(); !contains(header, "Poll: 1s ") || contains(header, "set") {
+		t.Errorf("Expected only the configured interval, got:\n%s", header)
+	}
+
+	configure(t, m.session, func(c *domain.Config) { c.AdaptivePolling = true })
+	if _, err := m.session.services.Monitor.Tick(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := m.session.services.Monitor.Tick(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if header := m.renderHeader(); !contains(header, "Poll: 2s (set 1s)") {
+		t.Errorf("Expected the effective interval next to the configured one, got:\n%s", header)
+	}
+}
+
+func TestModel_HeaderShowsCheckFailures(t *testing.T) {
+	tests := []struct {
+		err   error
+		label string
+	}{
+		{nil, "DISABLED"},
+		{&domain.CommandError{Command: "ifconfig awdl0", ExitCode: 1, Kind: domain.ErrInterfaceNotFound}, "ABSENT"},
+		{&domain.CommandError{Command: "ifconfig awdl0", ExitCode: 1, Kind: domain.ErrPermissionDenied}, "NO PERMISSION"},
+		{&domain.CommandError{Command: "ifconfig awdl0", ExitCode: 2}, "CHECK FAILED"},
+	}
+
+	for _, tt := range tests {
+		s, network, _ := newTestSession()
+		network.err = tt.err
+		if tt.err != nil {
+			network.status = domain.StatusUnknown
+		}
+
+		m := NewModel(s.services)
+		events, err := s.services.Monitor.Tick(context.Background())
+		updated, _ := m.Update(checkResultMsg{Events: events, Err: err})
+		m = updated.(Model)
+
+		if header := m.renderHeader(); !contains(header, " "+tt.label+" ") {
+			t.Errorf("Expected %q in the header, got:\n%s", tt.label, header)
+		}
+	}
+}
+
+func TestModel_ChecksAreSingleFlight(t *testing.T) {
+	m := newTestModel()
+
+	for i := 0; i < 3; i++ {
+		updated, _ := m.Update(tickMsg{})
+		m = updated.(Model)
+	}
+
+	if !m.session.checking || m.session.delayedTicks != 1 || m.session.skippedTicks != 1 {
+		t.Fatalf("Expected one check with one delayed and one skipped tick, got checking=%v delayed=%d skipped=%d",
+			m.session.checking, m.session.delayedTicks, m.session.skippedTicks)
+	}
+	if header := m.renderHeader(); !contains(header, "1 delayed, 1 skipped") {
+		t.Errorf("Expected the tick counts in the header, got:\n%s", header)
+	}
+
+	// The delayed tick runs once the first check is done
+	updated, _ := m.Update(checkResultMsg{})
+	m = updated.(Model)
+	if !m.session.checking || m.session.checkPending {
+		t.Error("Expected the delayed check to start")
+	}
+
+	updated, _ = m.Update(checkResultMsg{})
+	m = updated.(Model)
+	if m.session.checking {
+		t.Error("Expected no check to be running")
+	}
+}
+
+func TestModel_ResumeDropsStaleTicks(t *testing.T) {
+	m := newTestModel()
+
+	// Pausing and resuming before the pending tick fires starts a new chain
+	updated, _ := m.Update(keyPress("space"))
+	m = updated.(Model)
+	updated, cmd := m.Update(keyPress("space"))
+	m = updated.(Model)
+	if cmd == nil {
+		t.Fatal("Expected resuming to schedule a tick")
+	}
+
+	updated, _ = m.Update(tickMsg{gen: 0})
+	m = updated.(Model)
+	if m.session.checking {
+		t.Fatal("Expected the tick of the old chain to be dropped")
+	}
+
+	updated, _ = m.Update(tickMsg{gen: m.session.tickGen})
+	m = updated.(Model)
+	updated, _ = m.Update(tickMsg{gen: 0})
+	m = updated.(Model)
+	if !m.session.checking || m.session.delayedTicks != 0 || m.session.skippedTicks != 0 {
+		t.Errorf("Expected one check and no overlaps, got checking=%v delayed=%d skipped=%d",
+			m.session.checking, m.session.delayedTicks, m.session.skippedTicks)
+	}
+}
+
+func TestModel_DescribesOnlyWhenShown(t *testing.T) {
+	m := newTestModel()
+
+	updated, _ := m.Update(checkResultMsg{})
+	m = updated.(Model)
+	if m.session.describing {
+		t.Fatal("Expected no details to be queried while the Interfaces tab is hidden")
+	}
+
+	// Showing the tab queries the details right away
+	updated, cmd := m.Update(keyPress("5"))
+	m = updated.(Model)
+	if !m.session.describing {
+		t.Fatal("Expected the details to be queried once the tab is shown")
+	}
+	msgs := runCmd(cmd)
+
+	// Polls meanwhile wait for the running query and share one more
+	for i := 0; i < 3; i++ {
+		updated, _ = m.Update(checkResultMsg{})
+		m = updated.(Model)
+	}
+	if !m.session.describePending {
+		t.Fatal("Expected a query to be pending")
+	}
+
+	var details tea.Msg
+	for _, msg := range msgs {
+		if _, ok := msg.(detailsMsg); ok {
+			details = msg
+		}
+	}
+	if details == nil {
+		t.Fatalf("Expected the details, got %v", msgs)
+	}
+	updated, _ = m.Update(details)
+	m = updated.(Model)
+	if !m.session.describing || m.session.describePending {
+		t.Error("Expected the pending query to start")
+	}
+
+	updated, _ = m.Update(details)
+	m = updated.(Model)
+	if m.session.describing {
+		t.Error("Expected no query to be running")
+	}
+}
+
+func TestModel_ObserveOnly(t *testing.T) {
+	m := newTestModel()
+	if _, cmd := m.Update(keyPress("U")); cmd != nil {
+		for _, msg := range runCmd(cmd) {
+			if _, ok := msg.(tea.QuitMsg); ok {
+				t.Fatal("Expected U to do nothing while enforcing")
+			}
+		}
+	}
+
+	s, network, _ := newTestSession()
+	s.services.Monitor.WithObserveOnly()
+	network.status = domain.StatusUp
+
+	m = NewModel(s.services)
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = updated.(Model)
+
+	events, err := s.services.Monitor.Tick(context.Background())
+	updated, _ = m.Update(checkResultMsg{Events: events, Err: err})
+	m = updated.(Model)
+
+	if network.disabled != 0 {
+		t.Error("Expected nothing to be disabled")
+	}
+	if header := m.renderHeader(); !contains(header, "OBSERVE ONLY") || !contains(header, "ENABLED") {
+		t.Errorf("Expected the observe-only badge and the real status, got:\n%s", header)
+	}
+	if view := m.View(); !contains(view, "1 disables skipped") || !contains(view, "Would disable") {
+		t.Errorf("Expected the dashboard to show what would have been done, got:\n%s", view)
+	}
+
+	updated, cmd := m.Update(keyPress("U"))
+	m = updated.(Model)
+	if _, ok := cmd().(tea.QuitMsg); !ok || !m.ElevateRequested() {
+		t.Error("Expected U to quit and ask for sudo")
+	}
+}
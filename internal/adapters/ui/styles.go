@@ -1,6 +1,16 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
 
 type Styles struct {
 	Header        lipgloss.Style
@@ -13,41 +23,248 @@ type Styles struct {
 	Logs          lipgloss.Style
 	Timestamp     lipgloss.Style
 	SideEffects   lipgloss.Style
+	TabActive     lipgloss.Style
+	TabInactive   lipgloss.Style
+	Error         lipgloss.Style
+	HelpKey       lipgloss.Style
+	HelpDesc      lipgloss.Style
+}
+
+// palette holds the colors a theme is built from
+type palette struct {
+	Accent     lipgloss.TerminalColor
+	OnAccent   lipgloss.TerminalColor
+	Muted      lipgloss.TerminalColor
+	Subtle     lipgloss.TerminalColor
+	Success    lipgloss.TerminalColor
+	Danger     lipgloss.TerminalColor
+	Warning    lipgloss.TerminalColor
+	ErrorColor lipgloss.TerminalColor
+}
+
+var palettes = map[string]palette{
+	domain.ThemeDark: {
+		Accent:     lipgloss.Color("#7D56F4"),
+		OnAccent:   lipgloss.Color("#FAFAFA"),
+		Muted:      lipgloss.Color("#626262"),
+		Subtle:     lipgloss.Color("#888888"),
+		Success:    lipgloss.Color("#04B575"),
+		Danger:     lipgloss.Color("#FF0000"),
+		Warning:    lipgloss.Color("#FFFF00"),
+		ErrorColor: lipgloss.Color("196"),
+	},
+	domain.ThemeLight: {
+		Accent:     lipgloss.Color("#5A3FC0"),
+		OnAccent:   lipgloss.Color("#FFFFFF"),
+		Muted:      lipgloss.Color("#6C6C6C"),
+		Subtle:     lipgloss.Color("#4E4E4E"),
+		Success:    lipgloss.Color("#00794C"),
+		Danger:     lipgloss.Color("#C00000"),
+		Warning:    lipgloss.Color("#8A5A00"),
+		ErrorColor: lipgloss.Color("#C00000"),
+	},
+	domain.ThemeHighContrast: {
+		Accent:     lipgloss.Color("#00FFFF"),
+		OnAccent:   lipgloss.Color("#000000"),
+		Muted:      lipgloss.Color("#FFFFFF"),
+		Subtle:     lipgloss.Color("#FFFFFF"),
+		Success:    lipgloss.Color("#00FF00"),
+		Danger:     lipgloss.Color("#FF5555"),
+		Warning:    lipgloss.Color("#FFFF00"),
+		ErrorColor: lipgloss.Color("#FF5555"),
+	},
+	domain.ThemeMonochrome: {
+		Accent:     lipgloss.NoColor{},
+		OnAccent:   lipgloss.NoColor{},
+		Muted:      lipgloss.NoColor{},
+		Subtle:     lipgloss.NoColor{},
+		Success:    lipgloss.NoColor{},
+		Danger:     lipgloss.NoColor{},
+		Warning:    lipgloss.NoColor{},
+		ErrorColor: lipgloss.NoColor{},
+	},
+}
+
+// Appearance describes the terminal the TUI runs in
+type Appearance struct {
+	NoColor        bool
+	DarkBackground bool
+}
+
+// DetectAppearance reads NO_COLOR and asks the terminal for its background
+func DetectAppearance() Appearance {
+	return Appearance{
+		NoColor:        os.Getenv("NO_COLOR") != "",
+		DarkBackground: lipgloss.HasDarkBackground(),
+	}
+}
+
+// ResolveTheme turns the configured theme into a concrete one. NO_COLOR always
+// wins, and the auto theme follows the terminal background.
+func ResolveTheme(theme string, a Appearance) string {
+	if a.NoColor {
+		return domain.ThemeMonochrome
+	}
+
+	if _, ok := palettes[theme]; ok {
+		return theme
+	}
+
+	if a.DarkBackground {
+		return domain.ThemeDark
+	}
+	return domain.ThemeLight
 }
 
 func DefaultStyles() Styles {
-	return Styles{
+	return themeStyles(domain.ThemeDark)
+}
+
+// NewStyles builds the styles of the configured theme and applies the
+// configured overrides on top
+func NewStyles(c *domain.Config, a Appearance) (Styles, error) {
+	styles := themeStyles(ResolveTheme(c.Theme, a))
+	if err := styles.apply(c.Styles); err != nil {
+		return DefaultStyles(), err
+	}
+	return styles, nil
+}
+
+func themeStyles(theme string) Styles {
+	p := palettes[theme]
+
+	styles := Styles{
 		Header: lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
+			Foreground(p.OnAccent).
+			Background(p.Accent).
 			Padding(0, 1),
 		Footer: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
+			Foreground(p.Muted).
 			Padding(1, 0),
 		StatusUp: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#04B575")).
+			Foreground(p.Success).
 			Bold(true),
 		StatusDown: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF0000")).
+			Foreground(p.Danger).
 			Bold(true),
 		StatusUnknown: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
+			Foreground(p.Muted).
 			Bold(true),
 		Bar: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7D56F4")),
+			Foreground(p.Accent),
 		Dashboard: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(p.Accent).
 			Padding(1, 2),
 		Logs: lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(p.Accent).
 			Padding(0, 1),
 		Timestamp: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#888888")),
+			Foreground(p.Subtle),
 		SideEffects: lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFF00")).
+			Foreground(p.Warning).
 			MarginTop(1),
+		TabActive: lipgloss.NewStyle().
+			Bold(true).
+			Foreground(p.OnAccent).
+			Background(p.Accent),
+		TabInactive: lipgloss.NewStyle().
+			Foreground(p.Subtle),
+		Error: lipgloss.NewStyle().
+			Foreground(p.ErrorColor),
+		HelpKey: lipgloss.NewStyle().
+			Foreground(p.Subtle),
+		HelpDesc: lipgloss.NewStyle().
+			Foreground(p.Muted),
+	}
+
+	// Without colors, emphasis has to come from text attributes
+	if theme == domain.ThemeMonochrome {
+		styles.Header = styles.Header.Reverse(true)
+		styles.TabActive = styles.TabActive.Reverse(true)
+		styles.StatusDown = styles.StatusDown.Underline(true)
+		styles.Error = styles.Error.Bold(true).Underline(true)
+		styles.HelpKey = styles.HelpKey.Bold(true)
 	}
+
+	return styles
+}
+
+// byName maps the names used in the config file to each style
+func (s *Styles) byName() map[string]*lipgloss.Style {
+	return map[string]*lipgloss.Style{
+		"header":         &s.Header,
+		"footer":         &s.Footer,
+		"status_up":      &s.StatusUp,
+		"status_down":    &s.StatusDown,
+		"status_unknown": &s.StatusUnknown,
+		"bar":            &s.Bar,
+		"dashboard":      &s.Dashboard,
+		"logs":           &s.Logs,
+		"timestamp":      &s.Timestamp,
+		"side_effects":   &s.SideEffects,
+		"tab_active":     &s.TabActive,
+		"tab_inactive":   &s.TabInactive,
+		"error":          &s.Error,
+		"help_key":       &s.HelpKey,
+		"help_desc":      &s.HelpDesc,
+	}
+}
+
+func (s *Styles) apply(overrides map[string]domain.StyleOverride) error {
+	styles := s.byName()
+
+	var unknown []string
+	for name, o := range overrides {
+		style, ok := styles[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		if o.Foreground != "" {
+			*style = style.Foreground(lipgloss.Color(o.Foreground))
+		}
+		if o.Background != "" {
+			*style = style.Background(lipgloss.Color(o.Background))
+		}
+		if o.Bold != nil {
+			*style = style.Bold(*o.Bold)
+		}
+		if o.Italic != nil {
+			*style = style.Italic(*o.Italic)
+		}
+		if o.Underline != nil {
+			*style = style.Underline(*o.Underline)
+		}
+	}
+
+	if len(unknown) > 0 {
+		names := make([]string, 0, len(styles))
+		for name := range styles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		sort.Strings(unknown)
+
+		return fmt.Errorf("unknown style(s) %s, expected one of %s",
+			strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+
+	return nil
+}
+
+// helpStyles renders the help with the theme's colors
+func (s Styles) helpStyles() help.Styles {
+	styles := help.New().Styles
+	styles.ShortKey = s.HelpKey
+	styles.ShortDesc = s.HelpDesc
+	styles.ShortSeparator = s.HelpDesc
+	styles.FullKey = s.HelpKey
+	styles.FullDesc = s.HelpDesc
+	styles.FullSeparator = s.HelpDesc
+	styles.Ellipsis = s.HelpDesc
+	return styles
 }
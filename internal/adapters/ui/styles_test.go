@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestResolveTheme(t *testing.T) {
+	tests := []struct {
+		name       string
+		theme      string
+		appearance Appearance
+		want       string
+	}{
+		{"Auto On Dark", domain.ThemeAuto, Appearance{DarkBackground: true}, domain.ThemeDark},
+		{"Auto On Light", domain.ThemeAuto, Appearance{DarkBackground: false}, domain.ThemeLight},
+		{"Explicit Theme", domain.ThemeLight, Appearance{DarkBackground: true}, domain.ThemeLight},
+		{"High Contrast", domain.ThemeHighContrast, Appearance{}, domain.ThemeHighContrast},
+		{"NO_COLOR Wins", domain.ThemeDark, Appearance{NoColor: true, DarkBackground: true}, domain.ThemeMonochrome},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveTheme(tt.theme, tt.appearance); got != tt.want {
+				t.Errorf("ResolveTheme() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStyles_Monochrome(t *testing.T) {
+	config := domain.DefaultConfig()
+	styles, err := NewStyles(config, Appearance{NoColor: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if _, ok := styles.StatusDown.GetForeground().(lipgloss.NoColor); !ok {
+		t.Errorf("Expected no foreground color, got %v", styles.StatusDown.GetForeground())
+	}
+	if !styles.Header.GetReverse() {
+		t.Error("Expected the header to use reverse video instead of colors")
+	}
+}
+
+func TestNewStyles_Overrides(t *testing.T) {
+	bold := false
+	config := domain.DefaultConfig()
+	config.Styles = map[string]domain.StyleOverride{
+		"header": {Foreground: "#123456", Bold: &bold},
+	}
+
+	styles, err := NewStyles(config, Appearance{DarkBackground: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got := styles.Header.GetForeground(); got != lipgloss.Color("#123456") {
+		t.Errorf("Expected the header foreground to be overridden, got %v", got)
+	}
+	if styles.Header.GetBold() {
+		t.Error("Expected the header to no longer be bold")
+	}
+	if got := styles.Header.GetBackground(); got != palettes[domain.ThemeDark].Accent {
+		t.Errorf("Expected the theme background to be kept, got %v", got)
+	}
+}
+
+func TestNewStyles_UnknownStyle(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.Styles = map[string]domain.StyleOverride{"sparkles": {Foreground: "1"}}
+
+	if _, err := NewStyles(config, Appearance{}); err == nil || !strings.Contains(err.Error(), "sparkles") {
+		t.Errorf("Expected an error naming the unknown style, got %v", err)
+	}
+}
+
+func TestStyleOverrides_RoundTrip(t *testing.T) {
+	value := "error=fg:9 underline, header=fg:#fff bg:#000 nobold"
+
+	overrides, err := parseStyleOverrides(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := formatStyleOverrides(overrides); got != value {
+		t.Errorf("Expected %q, got %q", value, got)
+	}
+
+	if _, err := parseStyleOverrides("header=sparkly"); err == nil {
+		t.Error("Expected unknown attributes to be rejected")
+	}
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// dashboardTab shows the last hour of activity and the side effects of
+// keeping awdl0 down
+type dashboardTab struct {
+	session *session
+}
+
+func newDashboardTab(s *session) dashboardTab {
+	return dashboardTab{session: s}
+}
+
+func (t dashboardTab) Title() string {
+	return "Dashboard"
+}
+
+func (t dashboardTab) KeyBindings() []key.Binding {
+	return []key.Binding{t.session.keys.Toggle}
+}
+
+func (t dashboardTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok && key.Matches(msg, t.session.keys.Toggle) {
+		return t, t.session.toggleInterfaceCmd()
+	}
+
+	return t, nil
+}
+
+func (t dashboardTab) View(width, height int) string {
+	styles := t.session.styles
+
+	graph := renderHistogram(styles, t.session.buckets)
+
+	stats := fmt.Sprintf("Last Hour Activity: %d buckets", len(t.session.buckets))
+
+	lines := []string{stats}
+	if len(t.session.services.Config.ProcessRules) > 0 {
+		lines = append(lines, t.renderRule())
+	}
+	if t.session.services.Monitor.ObserveOnly() {
+		lines = append(lines, t.renderWouldDisable())
+	}
+
+	dashboardContent := lipgloss.JoinVertical(lipgloss.Center, append(lines, "\n", graph)...)
+
+	box := styles.Dashboard.Render(dashboardContent)
+
+	if t.session.awdl0Status == domain.StatusDown {
+		sideEffects := []string{
+			"• AirDrop: Disabled",
+			"• AirPlay & Sidecar: Impacted",
+			"• Continuity & Handoff: Interrupted",
+			"• Watch Unlock: Disabled",
+		}
+		sideEffectsView := styles.SideEffects.Render(strings.Join(sideEffects, "  "))
+		box = lipgloss.JoinVertical(lipgloss.Center, box, sideEffectsView)
+	}
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderRule names the process rule in effect, if any
+func (t dashboardTab) renderRule() string {
+	rule, ok := t.session.services.Monitor.ActiveRule()
+	if !ok {
+		return "Active rule: none"
+	}
+
+	style := t.session.styles.StatusUp
+	if rule.Mode == domain.ModeObserve {
+		style = t.session.styles.StatusUnknown
+	}
+	return "Active rule: " + rule.Name + " " + style.Render(" "+strings.ToUpper(string(rule.Mode))+" ")
+}
+
+// renderWouldDisable tells how often the monitor would have disabled an
+// interface had it been enforcing
+func (t dashboardTab) renderWouldDisable() string {
+	summary := t.session.services.Stats.GetSummary(t.session.ctx, time.Hour)
+	line := fmt.Sprintf("Observe only: %d disables skipped in the last hour", summary.WouldDisables)
+	if t.session.lastWouldDisable != nil {
+		line += "\nLast: " + t.session.lastWouldDisable.Timestamp.Format("15:04:05") + " " + t.session.lastWouldDisable.Message
+	}
+	return t.session.styles.StatusUnknown.Render(line)
+}
+
+// renderHistogram draws one block character per bucket, scaled to the
+// busiest bucket
+func renderHistogram(styles Styles, buckets []domain.Bucket) string {
+	maxCount := 0
+	for _, b := range buckets {
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+	if maxCount == 0 {
+		maxCount = 1
+	}
+
+	var bars []string
+
+	blocks := []string{" ", " ", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+	for _, b := range buckets {
+		height := 0
+		if b.Count <= 0 {
+			bars = append(bars, styles.Bar.Render(blocks[0]))
+			continue
+		}
+
+		height = int(float64(b.Count) / float64(maxCount) * 8.0)
+		if height > 8 {
+			height = 8
+		}
+		if height < 1 {
+			height = 1
+		}
+
+		bars = append(bars, styles.Bar.Render(blocks[height]))
+	}
+
+	return strings.Join(bars, "")
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func contains(s, substr string) bool {
+	return strings.Contains(s, substr)
+}
+
+func TestDashboardTab_ToggleKey(t *testing.T) {
+	s, network, _ := newTestSession()
+	dashboard := newDashboardTab(s)
+
+	_, cmd := dashboard.Update(keyPress("e"))
+	msgs := runCmd(cmd)
+
+	if len(msgs) != 1 {
+		t.Fatalf("Expected one message, got %d", len(msgs))
+	}
+	toggle, ok := msgs[0].(actionMsg)
+	if !ok || len(toggle.Events) != 1 || toggle.Events[0].Type != domain.EventEnable {
+		t.Errorf("Expected an enable event, got %#v", msgs[0])
+	}
+	if network.enabled != 1 {
+		t.Errorf("Expected awdl0 to be enabled once, got %d", network.enabled)
+	}
+}
+
+func TestDashboardTab_SideEffectsWhenDown(t *testing.T) {
+	s, _, _ := newTestSession()
+	dashboard := newDashboardTab(s)
+
+	if contains(dashboard.View(120, 30), "AirDrop") {
+		t.Error("Expected no side effects while the status is unknown")
+	}
+
+	s.awdl0Status = domain.StatusDown
+	if !contains(dashboard.View(120, 30), "AirDrop") {
+		t.Error("Expected side effects while awdl0 is down")
+	}
+}
+
+func TestDashboardTab_ShowsActiveRule(t *testing.T) {
+	s, _, _ := newTestSession()
+	dashboard := newDashboardTab(s)
+
+	if contains(dashboard.View(120, 30), "Active rule") {
+		t.Error("Expected no rule line without process rules")
+	}
+
+	configure(t, s, func(c *domain.Config) {
+		c.ProcessRules = []domain.ProcessRule{
+			{Name: "Calls", Processes: []string{"zoom.us"}, Mode: domain.ModeEnforce},
+		}
+	})
+	if !contains(dashboard.View(120, 30), "Active rule: none") {
+		t.Error("Expected the dashboard to show that no rule is active")
+	}
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// interfacesTab shows live details of every guarded interface, refreshed
+// after each poll while the tab is shown, and picks which interfaces are
+// guarded
+type interfacesTab struct {
+	session *session
+
+	details []domain.Interface
+	errs    map[string]error
+	// changed holds, per interface, the flags set or cleared since the
+	// previous poll
+	changed map[string]map[string]bool
+
+	picker *interfacePicker
+}
+
+// interfacePicker lists every interface of the system so the guarded ones
+// can be marked
+type interfacePicker struct {
+	choices []domain.Interface
+	marked  map[string]bool
+	cursor  int
+	err     string
+}
+
+func newInterfacesTab(s *session) interfacesTab {
+	return interfacesTab{session: s}
+}
+
+func (t interfacesTab) Title() string {
+	return "Interfaces"
+}
+
+func (t interfacesTab) KeyBindings() []key.Binding {
+	keys := t.session.keys
+	if t.picker != nil {
+		return []key.Binding{keys.Up, keys.Down, keys.Mark, keys.Apply, keys.Revert}
+	}
+	return []key.Binding{keys.Toggle, keys.Pick}
+}
+
+// capturesInput is true while the picker is open
+func (t interfacesTab) capturesInput() bool {
+	return t.picker != nil
+}
+
+func (t interfacesTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if t.picker != nil {
+			return t.updatePicker(msg)
+		}
+
+		switch {
+		case key.Matches(msg, t.session.keys.Toggle):
+			return t, t.session.toggleInterfaceCmd()
+		case key.Matches(msg, t.session.keys.Pick):
+			return t, t.session.listInterfacesCmd()
+		}
+
+	case interfaceListMsg:
+		if msg.Err != nil {
+			return t, notify(fmt.Sprintf("Error listing interfaces: %v", msg.Err))
+		}
+		t.picker = newInterfacePicker(msg.Interfaces, t.session.services.Config.Interfaces)
+
+	case detailsMsg:
+		t.changed = make(map[string]map[string]bool)
+		for _, iface := range msg.Interfaces {
+			if previous, ok := t.find(iface.Name); ok {
+				t.changed[iface.Name] = changedFlags(previous.Flags, iface.Flags)
+			}
+		}
+		t.details = msg.Interfaces
+		t.errs = msg.Errs
+	}
+
+	return t, nil
+}
+
+// newInterfacePicker marks the guarded interfaces. Guarded interfaces the
+// system does not have are listed as absent, so they can be unmarked.
+func newInterfacePicker(ifaces []domain.Interface, guarded []string) *interfacePicker {
+	p := &interfacePicker{marked: make(map[string]bool)}
+
+	listed := make(map[string]bool)
+	for _, iface := range ifaces {
+		listed[iface.Name] = true
+		p.choices = append(p.choices, iface)
+	}
+	for _, name := range guarded {
+		p.marked[name] = true
+		if !listed[name] {
+			p.choices = append(p.choices, domain.Interface{Name: name, Status: domain.StatusAbsent})
+		}
+	}
+	return p
+}
+
+func (t interfacesTab) updatePicker(msg tea.KeyMsg) (tab, tea.Cmd) {
+	keys := t.session.keys
+	p := t.picker
+
+	switch {
+	case key.Matches(msg, keys.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case key.Matches(msg, keys.Down):
+		if p.cursor < len(p.choices)-1 {
+			p.cursor++
+		}
+	case key.Matches(msg, keys.Mark):
+		if len(p.choices) > 0 {
+			name := p.choices[p.cursor].Name
+			p.marked[name] = !p.marked[name]
+		}
+	case key.Matches(msg, keys.Apply):
+		var names []string
+		for _, iface := range p.choices {
+			if p.marked[iface.Name] {
+				names = append(names, iface.Name)
+			}
+		}
+
+		candidate := *t.session.services.Config
+		candidate.Interfaces = names
+		var verrs domain.ValidationErrors
+		if errors.As(candidate.Validate(), &verrs) {
+			if msg, ok := verrs.Field("interfaces"); ok {
+				p.err = msg
+				return t, nil
+			}
+		}
+
+		t.picker = nil
+		return t, t.session.setInterfacesCmd(names)
+	case key.Matches(msg, keys.Revert):
+		t.picker = nil
+	}
+
+	return t, nil
+}
+
+// find returns the last details of the named interface
+func (t interfacesTab) find(name string) (domain.Interface, bool) {
+	for _, iface := range t.details {
+		if iface.Name == name {
+			return iface, true
+		}
+	}
+	return domain.Interface{}, false
+}
+
+// changedFlags returns the flags present in only one of both sets
+func changedFlags(before, after []string) map[string]bool {
+	changed := make(map[string]bool)
+	for _, f := range before {
+		changed[f] = true
+	}
+	for _, f := range after {
+		if changed[f] {
+			delete(changed, f)
+		} else {
+			changed[f] = true
+		}
+	}
+	return changed
+}
+
+func (t interfacesTab) View(width, height int) string {
+	styles := t.session.styles
+
+	if t.picker != nil {
+		box := styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, t.renderPicker()...))
+		return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+	}
+
+	lines := []string{"Guarded interfaces"}
+	if len(t.details) == 0 {
+		lines = append(lines, "", "Waiting for the first check...")
+	}
+	for _, iface := range t.details {
+		lines = append(lines, "")
+		lines = append(lines, t.renderInterface(iface)...)
+	}
+
+	box := styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderInterface draws the details panel of one interface
+func (t interfacesTab) renderInterface(iface domain.Interface) []string {
+	styles := t.session.styles
+
+	title := fmt.Sprintf("%s  %s", iface.Name, renderStatus(styles, iface.Status))
+	if since := t.session.services.Monitor.StateSince(iface.Name); !since.IsZero() {
+		title += fmt.Sprintf(" for %v", time.Since(since).Truncate(time.Second))
+	}
+
+	err := t.errs[iface.Name]
+	if err != nil && !errors.Is(err, domain.ErrInterfaceNotFound) {
+		return []string{title, "  " + styles.Error.Render(err.Error())}
+	}
+	if iface.Status == domain.StatusAbsent {
+		return []string{title, "  Not present on this machine"}
+	}
+
+	var linkLocal []string
+	for _, addr := range iface.Inet6 {
+		if strings.HasPrefix(addr, "fe80:") {
+			linkLocal = append(linkLocal, addr)
+		}
+	}
+
+	link := orDash(iface.LinkStatus)
+	if iface.Media != "" {
+		link += ", media " + iface.Media
+	}
+
+	return []string{
+		title,
+		"  MAC    " + orDash(iface.Ether),
+		fmt.Sprintf("  MTU    %d", iface.MTU),
+		"  Flags  " + t.renderFlags(iface),
+		"  IPv6   " + orDash(strings.Join(linkLocal, ", ")),
+		"  Link   " + link,
+	}
+}
+
+// renderFlags lists the flags, highlighting the ones set since the previous
+// poll and appending the ones cleared
+func (t interfacesTab) renderFlags(iface domain.Interface) string {
+	styles := t.session.styles
+	changed := t.changed[iface.Name]
+
+	var flags []string
+	for _, f := range iface.Flags {
+		if changed[f] {
+			flags = append(flags, styles.StatusUp.Render("+"+f))
+		} else {
+			flags = append(flags, f)
+		}
+	}
+
+	var cleared []string
+	for f := range changed {
+		if !iface.HasFlag(f) {
+			cleared = append(cleared, f)
+		}
+	}
+	sort.Strings(cleared)
+	for _, f := range cleared {
+		flags = append(flags, styles.StatusDown.Render("-"+f))
+	}
+
+	return orDash(strings.Join(flags, " "))
+}
+
+// renderStatus colors an interface status
+func renderStatus(styles Styles, status domain.Status) string {
+	switch status {
+	case domain.StatusUp:
+		return styles.StatusUp.Render(string(status))
+	case domain.StatusDown:
+		return styles.StatusDown.Render(string(status))
+	}
+	return styles.StatusUnknown.Render(string(status))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// renderPicker lists every interface with a mark for the guarded ones
+func (t interfacesTab) renderPicker() []string {
+	styles := t.session.styles
+	p := t.picker
+
+	lines := []string{"Pick guarded interfaces", ""}
+	for i, iface := range p.choices {
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+		mark := "[ ]"
+		if p.marked[iface.Name] {
+			mark = "[x]"
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s %-10s %s", cursor, mark, iface.Name, renderStatus(styles, iface.Status)))
+	}
+
+	if p.err != "" {
+		lines = append(lines, "", styles.Error.Render(p.err))
+	}
+	return lines
+}
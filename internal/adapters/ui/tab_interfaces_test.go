@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"context"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// poll runs a check and feeds the resulting details to the tab
+func poll(t *testing.T, s *session, ifaces interfacesTab) interfacesTab {
+	t.Helper()
+
+	if _, err := s.services.Monitor.Tick(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, msg := range runCmd(s.describeCmd()) {
+		updated, _ := ifaces.Update(msg)
+		ifaces = updated.(interfacesTab)
+	}
+	return ifaces
+}
+
+func TestInterfacesTab_ShowsDetails(t *testing.T) {
+	s, network, _ := newTestSession()
+	configure(t, s, func(c *domain.Config) { c.Schedule = &domain.Schedule{Default: domain.ModeObserve} })
+	ifaces := newInterfacesTab(s)
+
+	ifaces = poll(t, s, ifaces)
+
+	view := ifaces.View(120, 30)
+	for _, want := range []string{"awdl0  DOWN for", "6e:7e:67:aa:bb:cc", "MTU    1500"} {
+		if !contains(view, want) {
+			t.Errorf("Expected view to contain %q, got:\n%s", want, view)
+		}
+	}
+
+	network.status = domain.StatusUp
+	ifaces = poll(t, s, ifaces)
+
+	if changed := ifaces.changed["awdl0"]; !changed["UP"] || !changed["RUNNING"] {
+		t.Errorf("Expected UP and RUNNING to be highlighted, got %v", changed)
+	}
+	if !contains(ifaces.View(120, 30), "+UP") {
+		t.Error("Expected the newly set flag to be highlighted")
+	}
+
+	ifaces = poll(t, s, ifaces)
+	if len(ifaces.changed["awdl0"]) != 0 {
+		t.Errorf("Expected no changes on an unchanged poll, got %v", ifaces.changed["awdl0"])
+	}
+
+	network.status = domain.StatusDown
+	ifaces = poll(t, s, ifaces)
+	if !contains(ifaces.View(120, 30), "-UP") {
+		t.Error("Expected the cleared flag to be shown")
+	}
+}
+
+func TestChangedFlags(t *testing.T) {
+	changed := changedFlags([]string{"UP", "BROADCAST"}, []string{"BROADCAST", "PROMISC"})
+
+	if len(changed) != 2 || !changed["UP"] || !changed["PROMISC"] {
+		t.Errorf("Expected UP and PROMISC to have changed, got %v", changed)
+	}
+}
+
+func TestInterfacesTab_PickerSavesSelection(t *testing.T) {
+	s, _, saved := newTestSession()
+	ifaces := newInterfacesTab(s)
+
+	press := func(k string) tea.Cmd {
+		updated, cmd := ifaces.Update(keyPress(k))
+		ifaces = updated.(interfacesTab)
+		return cmd
+	}
+
+	for _, msg := range runCmd(press("i")) {
+		updated, _ := ifaces.Update(msg)
+		ifaces = updated.(interfacesTab)
+	}
+	if !ifaces.capturesInput() {
+		t.Fatal("Expected the picker to open")
+	}
+	if view := ifaces.View(120, 30); !contains(view, "[x] awdl0") || !contains(view, "[ ] llw0") {
+		t.Errorf("Expected awdl0 to be marked, got:\n%s", view)
+	}
+
+	// Unmarking everything is rejected
+	press("down")
+	press("down")
+	press("space")
+	press("enter")
+	if !ifaces.capturesInput() || ifaces.picker.err == "" {
+		t.Fatal("Expected an empty selection to be rejected")
+	}
+
+	press("down")
+	press("space")
+	msgs := settle(s, press("enter"))
+	if ifaces.capturesInput() {
+		t.Error("Expected the picker to close")
+	}
+	if len(msgs) != 2 {
+		t.Fatalf("Expected an action and a save, got %v", msgs)
+	}
+
+	if got := s.services.Config.Interfaces; len(got) != 1 || got[0] != "llw0" {
+		t.Errorf("Expected llw0 to be guarded, got %v", got)
+	}
+	if len(*saved) != 1 || (*saved)[0].Interfaces[0] != "llw0" {
+		t.Errorf("Expected the selection to be saved, got %v", *saved)
+	}
+}
+
+func TestInterfacesTab_PickerRefused(t *testing.T) {
+	s, _, saved := newTestSession()
+	s.services.Monitor.WithAllowList(refusingAllowList{})
+
+	msgs := settle(s, s.setInterfacesCmd([]string{"llw0"}))
+
+	if len(msgs) != 1 || msgs[0].(configAppliedMsg).Err == nil {
+		t.Fatalf("Expected the monitor to refuse the interfaces, got %v", msgs)
+	}
+	if got := s.services.Config.Interfaces; len(got) != 1 || got[0] != "awdl0" || len(*saved) != 0 {
+		t.Errorf("Expected refused interfaces to change nothing, got %v and %d saves", got, len(*saved))
+	}
+}
+
+func TestInterfacesTab_PickerCancel(t *testing.T) {
+	s, _, saved := newTestSession()
+	ifaces := newInterfacesTab(s)
+
+	updated, _ := ifaces.Update(interfaceListMsg{Interfaces: []domain.Interface{{Name: "en0", Status: domain.StatusUp}}})
+	ifaces = updated.(interfacesTab)
+
+	// Guarded interfaces the system lacks are still listed
+	if view := ifaces.View(120, 30); !contains(view, "[x] awdl0") || !contains(view, "Absent") {
+		t.Errorf("Expected the missing awdl0 to be listed, got:\n%s", view)
+	}
+
+	updated, _ = ifaces.Update(keyPress("space"))
+	ifaces = updated.(interfacesTab)
+	updated, cmd := ifaces.Update(keyPress("esc"))
+	ifaces = updated.(interfacesTab)
+
+	if ifaces.capturesInput() || cmd != nil || len(*saved) != 0 {
+		t.Error("Expected esc to close the picker without saving")
+	}
+}
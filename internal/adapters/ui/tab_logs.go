@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// logsTab is a scrollable view over the whole event history. Only a bounded
+// window of it is kept in memory; older and newer chunks are loaded when the
+// viewport reaches either end.
+type logsTab struct {
+	session  *session
+	logs     logWindow
+	loading  bool
+	viewport viewport.Model
+}
+
+func newLogsTab(s *session, latest []domain.HistoryEntry) logsTab {
+	t := logsTab{
+		session:  s,
+		logs:     newLogWindow(latest),
+		viewport: viewport.New(0, 0),
+	}
+
+	// Initialize viewport content
+	t.viewport.SetContent(t.renderLogs())
+	t.viewport.GotoBottom()
+
+	return t
+}
+
+func (t logsTab) Title() string {
+	return "Logs"
+}
+
+func (t logsTab) KeyBindings() []key.Binding {
+	keys := t.session.keys
+	return []key.Binding{keys.Up, keys.Down, keys.PageUp, keys.PageDown, keys.Top, keys.Bottom}
+}
+
+func (t logsTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, t.session.keys.Top):
+			t.viewport.GotoTop()
+		case key.Matches(msg, t.session.keys.Bottom):
+			t.viewport.GotoBottom()
+		default:
+			// Bindings can change from the settings tab at any time
+			t.viewport.KeyMap = t.session.keys.viewportKeyMap()
+
+			var cmd tea.Cmd
+			t.viewport, cmd = t.viewport.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+		cmds = append(cmds, t.pageLogs())
+
+	case tea.WindowSizeMsg:
+		styles := t.session.styles
+		wasAtBottom := t.viewport.AtBottom()
+		t.viewport.Width = msg.Width - styles.Logs.GetHorizontalFrameSize()
+		t.viewport.Height = msg.Height - styles.Logs.GetVerticalFrameSize()
+		if wasAtBottom {
+			t.viewport.GotoBottom()
+		}
+
+	case checkResultMsg:
+		for _, evt := range msg.Events {
+			t.appendLiveEvent(evt)
+		}
+
+	case actionMsg:
+		for _, evt := range msg.Events {
+			t.appendLiveEvent(evt)
+		}
+
+	case historyLoadedMsg:
+		t.loading = false
+		if msg.Err != nil {
+			break
+		}
+
+		offset := t.viewport.YOffset
+		if msg.Older {
+			offset += t.logs.prepend(msg.Entries)
+		} else {
+			offset -= t.logs.appendNewer(msg.Entries)
+		}
+		t.viewport.SetContent(t.renderLogs())
+		t.viewport.SetYOffset(offset)
+	}
+
+	return t, tea.Batch(cmds...)
+}
+
+func (t logsTab) View(width, height int) string {
+	return t.session.styles.Logs.Render(t.viewport.View())
+}
+
+// pageLogs loads the next chunk of history once the viewport reaches either
+// end of the log window
+func (t *logsTab) pageLogs() tea.Cmd {
+	if t.loading {
+		return nil
+	}
+
+	if t.viewport.AtTop() && !t.logs.atStart {
+		if cursor, ok := t.logs.first(); ok {
+			t.loading = true
+			return t.loadOlderLogsCmd(cursor)
+		}
+	}
+
+	if t.viewport.AtBottom() && !t.logs.following {
+		if cursor, ok := t.logs.last(); ok {
+			t.loading = true
+			return t.loadNewerLogsCmd(cursor)
+		}
+	}
+
+	return nil
+}
+
+func (t logsTab) loadOlderLogsCmd(before domain.HistoryCursor) tea.Cmd {
+	history := t.session.services.History
+	return func() tea.Msg {
+		entries, err := history.Before(before, logChunkSize)
+		return historyLoadedMsg{Entries: entries, Older: true, Err: err}
+	}
+}
+
+func (t logsTab) loadNewerLogsCmd(after domain.HistoryCursor) tea.Cmd {
+	history := t.session.services.History
+	return func() tea.Msg {
+		entries, err := history.After(after, logChunkSize)
+		return historyLoadedMsg{Entries: entries, Older: false, Err: err}
+	}
+}
+
+func (t *logsTab) appendLiveEvent(evt domain.Event) {
+	wasAtBottom := t.viewport.AtBottom()
+	offset := t.viewport.YOffset - t.logs.appendLive(evt)
+
+	t.viewport.SetContent(t.renderLogs())
+	if wasAtBottom && t.logs.following {
+		t.viewport.GotoBottom()
+	} else {
+		t.viewport.SetYOffset(offset)
+	}
+}
+
+func (t logsTab) renderLogs() string {
+	var content strings.Builder
+	today := time.Now().Format("2006-01-02")
+	for _, entry := range t.logs.entries {
+		event := entry.Event
+		layout := "15:04:05"
+		// Older history spans several days, so show the date where it differs
+		if event.Timestamp.Format("2006-01-02") != today {
+			layout = "Jan 02 15:04:05"
+		}
+		timestamp := t.session.styles.Timestamp.Render(event.Timestamp.Format(layout))
+		message := event.Message
+		if event.UpDuration > 0 {
+			message += fmt.Sprintf(" (up %v)", event.UpDuration.Round(time.Millisecond))
+		}
+		content.WriteString(fmt.Sprintf("%s %s\n", timestamp, message))
+	}
+	return content.String()
+}
@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func historyEvents(n int) []domain.Event {
+	start := time.Now().Add(-time.Duration(n) * time.Second)
+	events := make([]domain.Event, n)
+	for i := range events {
+		events[i] = domain.Event{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Type:      domain.EventDisable,
+			Message:   fmt.Sprintf("event %d", i),
+		}
+	}
+	return events
+}
+
+// pageUp scrolls to the top and delivers the older chunk it requests
+func pageUp(t *testing.T, logs logsTab) logsTab {
+	t.Helper()
+
+	updated, cmd := logs.Update(keyPress("g"))
+	logs = updated.(logsTab)
+
+	for _, msg := range runCmd(cmd) {
+		updated, _ = logs.Update(msg)
+		logs = updated.(logsTab)
+	}
+	return logs
+}
+
+func TestLogsTab_LoadsOlderHistoryAtTop(t *testing.T) {
+	s, _, _ := newTestSession(historyEvents(250)...)
+	latest, _ := s.services.History.Latest(logChunkSize)
+
+	tab, _ := newLogsTab(s, latest).Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	logs := tab.(logsTab)
+
+	if len(logs.logs.entries) != logChunkSize {
+		t.Fatalf("Expected %d entries initially, got %d", logChunkSize, len(logs.logs.entries))
+	}
+	if got := logs.logs.entries[0].Event.Message; got != "event 150" {
+		t.Errorf("Expected the newest events to be loaded first, got %q", got)
+	}
+
+	logs = pageUp(t, logs)
+	logs = pageUp(t, logs)
+
+	if len(logs.logs.entries) != 250 {
+		t.Fatalf("Expected the whole history after paging, got %d", len(logs.logs.entries))
+	}
+	if !logs.logs.atStart {
+		t.Error("Expected the window to know it reached the start of history")
+	}
+	if got := logs.logs.entries[0].Event.Message; got != "event 0" {
+		t.Errorf("Expected the oldest event first, got %q", got)
+	}
+}
+
+func TestLogsTab_WindowIsBounded(t *testing.T) {
+	s, _, _ := newTestSession(historyEvents(maxLogEntries + 2*logChunkSize)...)
+	latest, _ := s.services.History.Latest(logChunkSize)
+
+	tab, _ := newLogsTab(s, latest).Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	logs := tab.(logsTab)
+
+	for i := 0; i < 6; i++ {
+		logs = pageUp(t, logs)
+	}
+
+	if len(logs.logs.entries) != maxLogEntries {
+		t.Fatalf("Expected the window to be capped at %d entries, got %d", maxLogEntries, len(logs.logs.entries))
+	}
+	if logs.logs.following {
+		t.Error("Expected the window to stop following live events once the newest were dropped")
+	}
+
+	// Live events are not appended while scrolled back
+	logs.appendLiveEvent(domain.Event{Timestamp: time.Now(), Message: "live"})
+	if n := len(logs.logs.entries); logs.logs.entries[n-1].Event.Message == "live" {
+		t.Error("Expected live events to be skipped while scrolled back")
+	}
+
+	// Scrolling back down reloads newer chunks until the window follows again
+	for i := 0; i < 5 && !logs.logs.following; i++ {
+		updated, cmd := logs.Update(keyPress("G"))
+		logs = updated.(logsTab)
+		for _, msg := range runCmd(cmd) {
+			updated, _ = logs.Update(msg)
+			logs = updated.(logsTab)
+		}
+	}
+
+	if !logs.logs.following {
+		t.Fatal("Expected the window to follow live events again at the bottom")
+	}
+	if len(logs.logs.entries) > maxLogEntries {
+		t.Errorf("Expected the window to stay bounded, got %d", len(logs.logs.entries))
+	}
+}
+
+func TestLogsTab_AppendsLiveEvents(t *testing.T) {
+	s, _, _ := newTestSession()
+	tab, _ := newLogsTab(s, nil).Update(tea.WindowSizeMsg{Width: 80, Height: 20})
+	logs := tab.(logsTab)
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	unlogged := domain.Event{Timestamp: now, Type: domain.EventCheck, Message: "awdl0 is UP"}
+	evt := domain.Event{Timestamp: now, Type: domain.EventDisable, Message: "awdl0 detected UP",
+		Cursor: domain.HistoryCursor{Day: today, Index: 7}}
+	tab, _ = logs.Update(checkResultMsg{Events: []domain.Event{unlogged, evt}})
+	logs = tab.(logsTab)
+
+	if len(logs.logs.entries) != 1 {
+		t.Fatalf("Expected only the logged event, got %d entries", len(logs.logs.entries))
+	}
+	if got := logs.logs.entries[0].Cursor; got != evt.Cursor {
+		t.Errorf("Expected the cursor the logger assigned, got %+v", got)
+	}
+	if !contains(logs.View(80, 20), "awdl0 detected UP") {
+		t.Error("Expected the live event to be rendered")
+	}
+}
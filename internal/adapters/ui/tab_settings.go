@@ -0,0 +1,729 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// settingsField maps one configuration field to a text input
+type settingsField struct {
+	// key matches the field name used by domain.FieldError
+	key   string
+	label string
+	get   func(c *domain.Config) string
+	set   func(c *domain.Config, value string) error
+}
+
+var settingsFields = []settingsField{
+	{
+		key:   "polling_interval",
+		label: "Polling interval",
+		get:   func(c *domain.Config) string { return c.PollingInterval.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 1s or 750ms")
+			}
+			c.PollingInterval = d
+			return nil
+		},
+	},
+	{
+		key:   "adaptive_polling",
+		label: "Adaptive polling",
+		get:   func(c *domain.Config) string { return strconv.FormatBool(c.AdaptivePolling) },
+		set: func(c *domain.Config, value string) error {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.New("expected true or false")
+			}
+			c.AdaptivePolling = b
+			return nil
+		},
+	},
+	{
+		key:   "adaptive_ceiling",
+		label: "Adaptive ceiling",
+		get:   func(c *domain.Config) string { return c.AdaptiveCeiling.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 10s")
+			}
+			c.AdaptiveCeiling = d
+			return nil
+		},
+	},
+	{
+		key:   "poll_jitter",
+		label: "Poll jitter",
+		get:   func(c *domain.Config) string { return c.PollJitter.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 200ms")
+			}
+			c.PollJitter = d
+			return nil
+		},
+	},
+	{
+		key:   "interfaces",
+		label: "Guarded interfaces",
+		get:   func(c *domain.Config) string { return strings.Join(c.Interfaces, ", ") },
+		set: func(c *domain.Config, value string) error {
+			var names []string
+			for _, name := range strings.Split(value, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					names = append(names, name)
+				}
+			}
+			c.Interfaces = names
+			return nil
+		},
+	},
+	{
+		key:   "network_backend",
+		label: "Network backend (restart)",
+		get:   func(c *domain.Config) string { return c.NetworkBackend },
+		set: func(c *domain.Config, value string) error {
+			c.NetworkBackend = value
+			return nil
+		},
+	},
+	{
+		key:   "ifconfig_path",
+		label: "ifconfig binary (restart)",
+		get:   func(c *domain.Config) string { return c.IfconfigPath },
+		set: func(c *domain.Config, value string) error {
+			c.IfconfigPath = value
+			return nil
+		},
+	},
+	{
+		key:   "sudo_path",
+		label: "sudo binary (restart)",
+		get:   func(c *domain.Config) string { return c.SudoPath },
+		set: func(c *domain.Config, value string) error {
+			c.SudoPath = value
+			return nil
+		},
+	},
+	{
+		key:   "operation_timeout",
+		label: "Operation timeout",
+		get:   func(c *domain.Config) string { return c.OperationTimeout.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 5s")
+			}
+			c.OperationTimeout = d
+			return nil
+		},
+	},
+	{
+		key:   "snooze_short",
+		label: "Short allow",
+		get:   func(c *domain.Config) string { return c.SnoozeShort.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 5m")
+			}
+			c.SnoozeShort = d
+			return nil
+		},
+	},
+	{
+		key:   "snooze_long",
+		label: "Long allow",
+		get:   func(c *domain.Config) string { return c.SnoozeLong.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 30m")
+			}
+			c.SnoozeLong = d
+			return nil
+		},
+	},
+	{
+		key:   "debounce_readings",
+		label: "Debounce readings",
+		get:   func(c *domain.Config) string { return strconv.Itoa(c.DebounceReadings) },
+		set: func(c *domain.Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New("expected a number of readings such as 3")
+			}
+			c.DebounceReadings = n
+			return nil
+		},
+	},
+	{
+		key:   "debounce_duration",
+		label: "Debounce time",
+		get:   func(c *domain.Config) string { return c.DebounceDuration.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 0s or 3s")
+			}
+			c.DebounceDuration = d
+			return nil
+		},
+	},
+	{
+		key:   "hysteresis_readings",
+		label: "Hysteresis readings",
+		get:   func(c *domain.Config) string { return strconv.Itoa(c.HysteresisReadings) },
+		set: func(c *domain.Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New("expected a number of readings such as 1")
+			}
+			c.HysteresisReadings = n
+			return nil
+		},
+	},
+	{
+		key:   "flap_threshold",
+		label: "Flap threshold",
+		get:   func(c *domain.Config) string { return strconv.Itoa(c.FlapThreshold) },
+		set: func(c *domain.Config, value string) error {
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return errors.New("expected a number of disables such as 30, or 0 for off")
+			}
+			c.FlapThreshold = n
+			return nil
+		},
+	},
+	{
+		key:   "flap_window",
+		label: "Flap window",
+		get:   func(c *domain.Config) string { return c.FlapWindow.String() },
+		set: func(c *domain.Config, value string) error {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return errors.New("expected a duration such as 10m")
+			}
+			c.FlapWindow = d
+			return nil
+		},
+	},
+	{
+		key:   "flap_strategy",
+		label: "Flap strategy",
+		get:   func(c *domain.Config) string { return c.FlapStrategy },
+		set: func(c *domain.Config, value string) error {
+			c.FlapStrategy = value
+			return nil
+		},
+	},
+	{
+		key:   "schedule",
+		label: "Schedule",
+		get:   func(c *domain.Config) string { return formatSchedule(c.Schedule) },
+		set: func(c *domain.Config, value string) error {
+			schedule, err := parseSchedule(value)
+			if err != nil {
+				return err
+			}
+			c.Schedule = schedule
+			return nil
+		},
+	},
+	{
+		key:   "process_rules",
+		label: "Process rules",
+		get:   func(c *domain.Config) string { return formatProcessRules(c.ProcessRules) },
+		set: func(c *domain.Config, value string) error {
+			rules, err := parseProcessRules(value)
+			if err != nil {
+				return err
+			}
+			c.ProcessRules = rules
+			return nil
+		},
+	},
+	{
+		key:   "key_bindings",
+		label: "Key bindings",
+		get:   func(c *domain.Config) string { return formatKeyBindings(c.KeyBindings) },
+		set: func(c *domain.Config, value string) error {
+			bindings, err := parseKeyBindings(value)
+			if err != nil {
+				return err
+			}
+
+			keys, err := NewKeyMap(bindings)
+			if err != nil {
+				return err
+			}
+			if conflicts := keys.Conflicts(); len(conflicts) > 0 {
+				return errors.New(conflicts[0])
+			}
+
+			c.KeyBindings = bindings
+			return nil
+		},
+	},
+	{
+		key:   "theme",
+		label: "Theme",
+		get:   func(c *domain.Config) string { return c.Theme },
+		set: func(c *domain.Config, value string) error {
+			c.Theme = value
+			return nil
+		},
+	},
+	{
+		key:   "styles",
+		label: "Style overrides",
+		get:   func(c *domain.Config) string { return formatStyleOverrides(c.Styles) },
+		set: func(c *domain.Config, value string) error {
+			overrides, err := parseStyleOverrides(value)
+			if err != nil {
+				return err
+			}
+
+			var styles Styles
+			if err := styles.apply(overrides); err != nil {
+				return err
+			}
+
+			c.Styles = overrides
+			return nil
+		},
+	},
+}
+
+// formatSchedule renders a schedule as
+// "observe; tz=Europe/Berlin; enforce mon-fri 09:00-18:00"
+func formatSchedule(schedule *domain.Schedule) string {
+	if schedule == nil {
+		return ""
+	}
+
+	parts := []string{string(schedule.Default)}
+	if schedule.Timezone != "" {
+		parts = append(parts, "tz="+schedule.Timezone)
+	}
+	for _, rule := range schedule.Rules {
+		parts = append(parts, string(rule.Mode)+" "+rule.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+func parseSchedule(value string) (*domain.Schedule, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	schedule := &domain.Schedule{}
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		fields := strings.Fields(part)
+
+		switch {
+		case strings.HasPrefix(part, "tz="):
+			schedule.Timezone = strings.TrimPrefix(part, "tz=")
+
+		case len(fields) == 1:
+			schedule.Default = domain.Mode(fields[0])
+
+		case len(fields) == 3:
+			start, end, ok := strings.Cut(fields[2], "-")
+			if !ok {
+				return nil, fmt.Errorf("expected HH:MM-HH:MM, got %q", fields[2])
+			}
+			schedule.Rules = append(schedule.Rules, domain.ScheduleRule{
+				Mode:  domain.Mode(fields[0]),
+				Days:  strings.Split(fields[1], ","),
+				Start: start,
+				End:   end,
+			})
+
+		default:
+			return nil, fmt.Errorf("expected \"mode days HH:MM-HH:MM\", got %q", part)
+		}
+	}
+
+	if schedule.Default == "" {
+		schedule.Default = domain.ModeEnforce
+	}
+	return schedule, nil
+}
+
+// formatProcessRules renders rules as
+// "Calls=enforce zoom.us|Microsoft Teams; Sidecar=observe SidecarRelay"
+func formatProcessRules(rules []domain.ProcessRule) string {
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = fmt.Sprintf("%s=%s %s", rule.Name, rule.Mode, strings.Join(rule.Processes, "|"))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func parseProcessRules(value string) ([]domain.ProcessRule, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var rules []domain.ProcessRule
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		name, rest, ok := strings.Cut(part, "=")
+		mode, processes, hasProcesses := strings.Cut(strings.TrimSpace(rest), " ")
+		if !ok || !hasProcesses {
+			return nil, fmt.Errorf("expected name=mode process|process, got %q", part)
+		}
+
+		rule := domain.ProcessRule{Name: strings.TrimSpace(name), Mode: domain.Mode(mode)}
+		for _, process := range strings.Split(processes, "|") {
+			if process = strings.TrimSpace(process); process != "" {
+				rule.Processes = append(rule.Processes, process)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// formatStyleOverrides renders overrides as "name=fg:#fff bg:#000 bold"
+func formatStyleOverrides(overrides map[string]domain.StyleOverride) string {
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		o := overrides[name]
+
+		var attrs []string
+		if o.Foreground != "" {
+			attrs = append(attrs, "fg:"+o.Foreground)
+		}
+		if o.Background != "" {
+			attrs = append(attrs, "bg:"+o.Background)
+		}
+		for _, flag := range []struct {
+			name  string
+			value *bool
+		}{{"bold", o.Bold}, {"italic", o.Italic}, {"underline", o.Underline}} {
+			if flag.value == nil {
+				continue
+			}
+			if *flag.value {
+				attrs = append(attrs, flag.name)
+			} else {
+				attrs = append(attrs, "no"+flag.name)
+			}
+		}
+
+		parts[i] = name + "=" + strings.Join(attrs, " ")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func parseStyleOverrides(value string) (map[string]domain.StyleOverride, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	overrides := make(map[string]domain.StyleOverride)
+	for _, part := range strings.Split(value, ",") {
+		name, attrs, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=attributes, got %q", strings.TrimSpace(part))
+		}
+
+		var o domain.StyleOverride
+		for _, attr := range strings.Fields(attrs) {
+			enabled := !strings.HasPrefix(attr, "no")
+			switch {
+			case strings.HasPrefix(attr, "fg:"):
+				o.Foreground = strings.TrimPrefix(attr, "fg:")
+			case strings.HasPrefix(attr, "bg:"):
+				o.Background = strings.TrimPrefix(attr, "bg:")
+			case strings.TrimPrefix(attr, "no") == "bold":
+				o.Bold = &enabled
+			case strings.TrimPrefix(attr, "no") == "italic":
+				o.Italic = &enabled
+			case strings.TrimPrefix(attr, "no") == "underline":
+				o.Underline = &enabled
+			default:
+				return nil, fmt.Errorf("unknown style attribute %q", attr)
+			}
+		}
+		overrides[name] = o
+	}
+	return overrides, nil
+}
+
+// formatKeyBindings renders overrides as "action=key|key, action=key"
+func formatKeyBindings(bindings map[string][]string) string {
+	actions := make([]string, 0, len(bindings))
+	for action := range bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	parts := make([]string, len(actions))
+	for i, action := range actions {
+		keys := make([]string, len(bindings[action]))
+		for j, k := range bindings[action] {
+			if k == " " {
+				k = "space"
+			}
+			keys[j] = k
+		}
+		parts[i] = action + "=" + strings.Join(keys, "|")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func parseKeyBindings(value string) (map[string][]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	bindings := make(map[string][]string)
+	for _, part := range strings.Split(value, ",") {
+		action, keys, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || action == "" || keys == "" {
+			return nil, fmt.Errorf("expected action=key|key, got %q", strings.TrimSpace(part))
+		}
+		for _, k := range strings.Split(keys, "|") {
+			bindings[action] = append(bindings[action], normalizeKey(strings.TrimSpace(k)))
+		}
+	}
+	return bindings, nil
+}
+
+// settingsTab is a form over every configuration field. Changes are kept in
+// the inputs until they are saved.
+type settingsTab struct {
+	session *session
+	inputs  []textinput.Model
+	focus   int
+	editing bool
+	errors  map[string]string
+	// confirmReset is set after the first reset key press; only a second
+	// one right after it resets
+	confirmReset bool
+}
+
+func newSettingsTab(s *session) settingsTab {
+	t := settingsTab{session: s}
+
+	for range settingsFields {
+		input := textinput.New()
+		input.Prompt = ""
+		input.CharLimit = 256
+		input.Width = 40
+		t.inputs = append(t.inputs, input)
+	}
+	t.load(s.services.Config)
+
+	return t
+}
+
+func (t settingsTab) Title() string {
+	return "Settings"
+}
+
+func (t settingsTab) KeyBindings() []key.Binding {
+	keys := t.session.keys
+	if t.editing {
+		return []key.Binding{keys.Apply, keys.Revert}
+	}
+	return []key.Binding{keys.Up, keys.Down, keys.Edit, keys.Save, keys.Cancel, keys.Reset}
+}
+
+// capturesInput is true while a field is being edited, so global keys are
+// typed into the field instead
+func (t settingsTab) capturesInput() bool {
+	return t.editing
+}
+
+func (t settingsTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	// Other tabs save the config too, e.g. the interface picker
+	if _, saved := msg.(configSavedMsg); saved && !t.editing {
+		t.load(t.session.services.Config)
+		return t, nil
+	}
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return t, nil
+	}
+
+	if t.editing {
+		return t.updateEditing(keyMsg)
+	}
+
+	keys := t.session.keys
+	confirming := t.confirmReset
+	t.confirmReset = false
+
+	switch {
+	case key.Matches(keyMsg, keys.Up):
+		if t.focus > 0 {
+			t.focus--
+		}
+
+	case key.Matches(keyMsg, keys.Down):
+		if t.focus < len(t.inputs)-1 {
+			t.focus++
+		}
+
+	case key.Matches(keyMsg, keys.Edit):
+		t.editing = true
+		return t, t.inputs[t.focus].Focus()
+
+	case key.Matches(keyMsg, keys.Save):
+		return t.save()
+
+	case key.Matches(keyMsg, keys.Cancel):
+		t.load(t.session.services.Config)
+		return t, notify("Changes discarded")
+
+	case key.Matches(keyMsg, keys.Reset):
+		if !confirming {
+			t.confirmReset = true
+			return t, notify(fmt.Sprintf("Press %s again to reset every setting, including the guarded interfaces", keys.Reset.Help().Key))
+		}
+		t.load(domain.DefaultConfig())
+		return t.save()
+	}
+
+	return t, nil
+}
+
+func (t settingsTab) updateEditing(msg tea.KeyMsg) (tab, tea.Cmd) {
+	field := settingsFields[t.focus]
+
+	switch {
+	case key.Matches(msg, t.session.keys.Apply):
+		t.editing = false
+		t.inputs[t.focus].Blur()
+		t.validateField(field)
+		return t, nil
+
+	case key.Matches(msg, t.session.keys.Revert):
+		t.editing = false
+		t.inputs[t.focus].Blur()
+		t.inputs[t.focus].SetValue(field.get(t.session.services.Config))
+		delete(t.errors, field.key)
+		return t, nil
+	}
+
+	var cmd tea.Cmd
+	t.inputs[t.focus], cmd = t.inputs[t.focus].Update(msg)
+	return t, cmd
+}
+
+// validateField checks a single edited field against the current config
+func (t *settingsTab) validateField(field settingsField) {
+	delete(t.errors, field.key)
+
+	candidate := *t.session.services.Config
+	if err := field.set(&candidate, strings.TrimSpace(t.inputs[t.focus].Value())); err != nil {
+		t.errors[field.key] = err.Error()
+		return
+	}
+
+	var verrs domain.ValidationErrors
+	if errors.As(candidate.Validate(), &verrs) {
+		if msg, ok := verrs.Field(field.key); ok {
+			t.errors[field.key] = msg
+		}
+	}
+}
+
+// save applies every input to a copy of the config and only hands it to the
+// monitor when the copy is valid. The session takes the copy once the monitor
+// has.
+func (t settingsTab) save() (tab, tea.Cmd) {
+	t.errors = map[string]string{}
+
+	candidate := *t.session.services.Config
+	for i, field := range settingsFields {
+		if err := field.set(&candidate, strings.TrimSpace(t.inputs[i].Value())); err != nil {
+			t.errors[field.key] = err.Error()
+		}
+	}
+
+	var verrs domain.ValidationErrors
+	if errors.As(candidate.Validate(), &verrs) {
+		for _, fe := range verrs {
+			if _, exists := t.errors[fe.Field]; !exists {
+				t.errors[fe.Field] = fe.Message
+			}
+		}
+	}
+
+	if len(t.errors) > 0 {
+		return t, notify("Error: settings are invalid, nothing was saved")
+	}
+
+	candidate.Clamp()
+	return t, t.session.updateConfigCmd(candidate.Clone())
+}
+
+// load fills every input from the given config and clears validation errors
+func (t *settingsTab) load(c *domain.Config) {
+	for i, field := range settingsFields {
+		t.inputs[i].SetValue(field.get(c))
+	}
+	t.errors = map[string]string{}
+}
+
+func (t settingsTab) View(width, height int) string {
+	styles := t.session.styles
+
+	labelWidth := 0
+	for _, field := range settingsFields {
+		labelWidth = max(labelWidth, lipgloss.Width(field.label))
+	}
+
+	rows := []string{"Settings", ""}
+	for i, field := range settingsFields {
+		cursor := "  "
+		if i == t.focus {
+			cursor = "> "
+		}
+
+		row := fmt.Sprintf("%s%-*s  %s", cursor, labelWidth, field.label, t.inputs[i].View())
+		if msg, ok := t.errors[field.key]; ok {
+			row += "  " + styles.Error.Render(msg)
+		}
+		rows = append(rows, row)
+	}
+
+	box := styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func notify(msg string) tea.Cmd {
+	return func() tea.Msg {
+		return notifyMsg(msg)
+	}
+}
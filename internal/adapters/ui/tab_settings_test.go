@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// typeInto replaces the focused field's value by editing it through the form
+func typeInto(t *testing.T, settings settingsTab, value string) settingsTab {
+	t.Helper()
+
+	updated, _ := settings.Update(keyPress("enter"))
+	settings = updated.(settingsTab)
+	if !settings.capturesInput() {
+		t.Fatal("Expected enter to start editing")
+	}
+
+	settings.inputs[settings.focus].SetValue("")
+	for _, r := range value {
+		updated, _ = settings.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		settings = updated.(settingsTab)
+	}
+
+	updated, _ = settings.Update(keyPress("enter"))
+	return updated.(settingsTab)
+}
+
+func TestSettingsTab_SaveValidConfig(t *testing.T) {
+	s, _, saved := newTestSession()
+	settings := newSettingsTab(s)
+
+	settings = typeInto(t, settings, "2500ms")
+	if s.services.Config.PollingInterval != time.Second {
+		t.Error("Expected the config to be unchanged until saved")
+	}
+
+	updated, cmd := settings.Update(keyPress("s"))
+	settings = updated.(settingsTab)
+	settle(s, cmd)
+
+	if s.services.Config.PollingInterval != 2500*time.Millisecond {
+		t.Errorf("Expected 2.5s, got %v", s.services.Config.PollingInterval)
+	}
+	if len(*saved) != 1 {
+		t.Errorf("Expected one save, got %d", len(*saved))
+	}
+}
+
+func TestSettingsTab_RejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{"Not A Duration", "fast"},
+		{"Below Minimum", "100ms"},
+		{"Above Maximum", "5m"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, saved := newTestSession()
+			settings := typeInto(t, newSettingsTab(s), tt.value)
+
+			if _, ok := settings.errors["polling_interval"]; !ok {
+				t.Error("Expected a validation message after editing")
+			}
+
+			updated, cmd := settings.Update(keyPress("s"))
+			settings = updated.(settingsTab)
+			msgs := runCmd(cmd)
+
+			if len(*saved) != 0 {
+				t.Error("Expected nothing to be saved")
+			}
+			if s.services.Config.PollingInterval != time.Second {
+				t.Errorf("Expected the config to be unchanged, got %v", s.services.Config.PollingInterval)
+			}
+			if len(msgs) != 1 || !contains(string(msgs[0].(notifyMsg)), "Error") {
+				t.Errorf("Expected an error notification, got %v", msgs)
+			}
+			if !contains(settings.View(120, 30), settings.errors["polling_interval"]) {
+				t.Error("Expected the validation message to be rendered")
+			}
+		})
+	}
+}
+
+func TestSettingsTab_CancelAndReset(t *testing.T) {
+	s, network, saved := newTestSession()
+	configure(t, s, func(c *domain.Config) {
+		c.Interfaces = []string{"awdl0", "llw0"}
+		c.PollingInterval = 3 * time.Second
+	})
+	settings := newSettingsTab(s)
+
+	settings = typeInto(t, settings, "10s")
+	updated, _ := settings.Update(keyPress("c"))
+	settings = updated.(settingsTab)
+
+	if got := settings.inputs[0].Value(); got != "3s" {
+		t.Errorf("Expected cancel to restore the saved value, got %q", got)
+	}
+
+	// The first press only asks for confirmation
+	updated, cmd := settings.Update(keyPress("r"))
+	settings = updated.(settingsTab)
+	runCmd(cmd)
+	if s.services.Config.PollingInterval != 3*time.Second || len(*saved) != 0 {
+		t.Fatalf("Expected nothing to be reset before confirming, got %+v", *s.services.Config)
+	}
+
+	updated, cmd = settings.Update(keyPress("r"))
+	settings = updated.(settingsTab)
+	settle(s, cmd)
+
+	if !reflect.DeepEqual(s.services.Config, domain.DefaultConfig()) {
+		t.Errorf("Expected defaults after reset, got %+v", *s.services.Config)
+	}
+	if len(*saved) != 1 {
+		t.Errorf("Expected reset to be saved, got %d saves", len(*saved))
+	}
+	if got := s.services.Monitor.GetConfig().Interfaces; !reflect.DeepEqual(got, []string{"awdl0"}) {
+		t.Errorf("Expected the monitor to guard the default interfaces, got %v", got)
+	}
+	if network.enabled != 1 {
+		t.Errorf("Expected the dropped llw0 to be enabled again, got %d enables", network.enabled)
+	}
+}
+
+func TestSettingsTab_ResetNeedsConfirmation(t *testing.T) {
+	s, _, saved := newTestSession()
+	s.services.Config.PollingInterval = 3 * time.Second
+	settings := newSettingsTab(s)
+
+	for _, k := range []string{"r", "down", "r"} {
+		updated, cmd := settings.Update(keyPress(k))
+		settings = updated.(settingsTab)
+		runCmd(cmd)
+	}
+
+	if s.services.Config.PollingInterval != 3*time.Second || len(*saved) != 0 {
+		t.Errorf("Expected another key to cancel the reset, got %+v", *s.services.Config)
+	}
+}
+
+func TestSettingsTab_EscRevertsField(t *testing.T) {
+	s, _, _ := newTestSession()
+	settings := newSettingsTab(s)
+
+	updated, _ := settings.Update(keyPress("enter"))
+	settings = updated.(settingsTab)
+	updated, _ = settings.Update(keyPress("x"))
+	settings = updated.(settingsTab)
+	updated, _ = settings.Update(keyPress("esc"))
+	settings = updated.(settingsTab)
+
+	if settings.capturesInput() {
+		t.Error("Expected esc to stop editing")
+	}
+	if got := settings.inputs[0].Value(); got != "1s" {
+		t.Errorf("Expected the field to be reverted, got %q", got)
+	}
+}
+
+func TestModel_EditingCapturesGlobalKeys(t *testing.T) {
+	m := newTestModel()
+
+	updated, _ := m.Update(keyPress("6"))
+	m = updated.(Model)
+	updated, _ = m.Update(keyPress("enter"))
+	m = updated.(Model)
+
+	// "q" and "1" are typed into the field instead of quitting or switching tabs
+	updated, cmd := m.Update(keyPress("q"))
+	m = updated.(Model)
+	for _, msg := range runCmd(cmd) {
+		if _, ok := msg.(tea.QuitMsg); ok {
+			t.Fatal("Expected q to be captured by the settings form")
+		}
+	}
+	updated, _ = m.Update(keyPress("1"))
+	m = updated.(Model)
+
+	if m.active != 5 {
+		t.Errorf("Expected to stay on the settings tab, got %d", m.active)
+	}
+}
+
+func TestSettingsTab_RefusedConfigChangesNothing(t *testing.T) {
+	s, _, saved := newTestSession()
+	s.services.Monitor.WithAllowList(refusingAllowList{})
+	settings := newSettingsTab(s)
+
+	for settingsFields[settings.focus].key != "key_bindings" {
+		updated, _ := settings.Update(keyPress("down"))
+		settings = updated.(settingsTab)
+	}
+	settings = typeInto(t, settings, "pause=p|space")
+
+	updated, cmd := settings.Update(keyPress("s"))
+	settings = updated.(settingsTab)
+	msgs := settle(s, cmd)
+
+	if len(msgs) != 1 || msgs[0].(configAppliedMsg).Err == nil {
+		t.Fatalf("Expected the monitor to refuse the config, got %v", msgs)
+	}
+	if len(s.services.Config.KeyBindings) != 0 || key.Matches(keyPress("p"), s.keys.Pause) || len(*saved) != 0 {
+		t.Errorf("Expected a refused config to change nothing, got %v and %d saves", s.services.Config.KeyBindings, len(*saved))
+	}
+	if got := settings.inputs[settings.focus].Value(); got != "pause=p|space" {
+		t.Errorf("Expected the edit to stay in the form, got %q", got)
+	}
+}
+
+func TestSettingsTab_KeyBindings(t *testing.T) {
+	s, _, _ := newTestSession()
+	settings := newSettingsTab(s)
+
+	for settingsFields[settings.focus].key != "key_bindings" {
+		updated, _ := settings.Update(keyPress("down"))
+		settings = updated.(settingsTab)
+	}
+
+	settings = typeInto(t, settings, "pause=e")
+	if msg := settings.errors["key_bindings"]; !contains(msg, "bound to both") {
+		t.Errorf("Expected a conflict message, got %q", msg)
+	}
+
+	settings = typeInto(t, settings, "pause=p|space")
+	updated, cmd := settings.Update(keyPress("s"))
+	settings = updated.(settingsTab)
+	settle(s, cmd)
+
+	if len(settings.errors) != 0 {
+		t.Fatalf("Expected valid bindings, got %v", settings.errors)
+	}
+	if got := settings.inputs[settings.focus].Value(); got != "pause=p|space" {
+		t.Errorf("Expected the saved bindings to round-trip, got %q", got)
+	}
+	if !key.Matches(keyPress("p"), s.keys.Pause) {
+		t.Error("Expected the new binding to take effect immediately")
+	}
+}
+
+func TestSettingsTab_ScheduleRoundTrip(t *testing.T) {
+	value := "observe; tz=UTC; enforce mon-fri 09:00-18:00; enforce sat,sun 22:00-02:00"
+
+	schedule, err := parseSchedule(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := schedule.Validate(); err != nil {
+		t.Fatalf("Expected a valid schedule, got %v", err)
+	}
+	if got := formatSchedule(schedule); got != value {
+		t.Errorf("Expected %q to round-trip, got %q", value, got)
+	}
+
+	if _, err := parseSchedule("enforce mon-fri"); err == nil {
+		t.Error("Expected a rule without hours to be rejected")
+	}
+}
+
+func TestSettingsTab_ProcessRulesRoundTrip(t *testing.T) {
+	value := "Calls=enforce zoom.us|Microsoft Teams; Sidecar=observe SidecarRelay"
+
+	rules, err := parseProcessRules(value)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Processes[1] != "Microsoft Teams" || rules[1].Mode != domain.ModeObserve {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+	if got := formatProcessRules(rules); got != value {
+		t.Errorf("Expected %q to round-trip, got %q", value, got)
+	}
+
+	if _, err := parseProcessRules("Calls=enforce"); err == nil {
+		t.Error("Expected a rule without processes to be rejected")
+	}
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// statsWindows are the time windows the stats tab can summarize
+var statsWindows = []time.Duration{
+	1 * time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// statsBuckets is the number of histogram buckets drawn for any window
+const statsBuckets = 48
+
+// statsTab summarizes the events of a selectable time window
+type statsTab struct {
+	session  *session
+	window   int
+	summary  domain.Summary
+	buckets  []domain.Bucket
+	exposure domain.Exposure
+}
+
+func newStatsTab(s *session) statsTab {
+	t := statsTab{session: s}
+	t.refresh()
+	return t
+}
+
+func (t statsTab) Title() string {
+	return "Stats"
+}
+
+func (t statsTab) KeyBindings() []key.Binding {
+	return []key.Binding{t.session.keys.Left, t.session.keys.Right}
+}
+
+func (t statsTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, t.session.keys.Left):
+			if t.window > 0 {
+				t.window--
+			}
+		case key.Matches(msg, t.session.keys.Right):
+			if t.window < len(statsWindows)-1 {
+				t.window++
+			}
+		default:
+			return t, nil
+		}
+		t.refresh()
+
+	case checkResultMsg, actionMsg:
+		t.refresh()
+	}
+
+	return t, nil
+}
+
+func (t *statsTab) refresh() {
+	window := statsWindows[t.window]
+	stats, ctx := t.session.services.Stats, t.session.ctx
+
+	t.summary = stats.GetSummary(ctx, window)
+	t.buckets = stats.GetHistogram(ctx, window, statsBuckets)
+	t.exposure = stats.GetExposure(ctx, window)
+}
+
+func (t statsTab) View(width, height int) string {
+	styles := t.session.styles
+	window := statsWindows[t.window]
+
+	last := "never"
+	if !t.summary.Last.IsZero() {
+		last = t.summary.Last.Format("Jan 02 15:04:05")
+	}
+
+	lines := []string{
+		fmt.Sprintf("Window: last %v", window),
+		"",
+		fmt.Sprintf("Events:   %d", t.summary.Total),
+		fmt.Sprintf("Disables: %d", t.summary.Disables),
+		fmt.Sprintf("Enables:  %d", t.summary.Enables),
+		fmt.Sprintf("Last:     %s", last),
+		"",
+		renderHistogram(styles, t.buckets),
+		"",
+	}
+	lines = append(lines, t.renderExposure()...)
+
+	box := styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderExposure shows how long guarded interfaces stayed up before they
+// were disabled, in total and per hour
+func (t statsTab) renderExposure() []string {
+	e := t.exposure
+
+	lines := []string{
+		fmt.Sprintf("Exposure: %v over %d disables, longest %v",
+			e.Total.Round(time.Millisecond), e.Disables, e.Longest.Round(time.Millisecond)),
+	}
+
+	var worst domain.HourlyExposure
+	hourly := make([]domain.Bucket, len(e.Hours))
+	for i, h := range e.Hours {
+		if h.Up > worst.Up {
+			worst = h
+		}
+		hourly[i] = domain.Bucket{Label: h.Hour.Format("15:04"), Count: int(h.Up.Milliseconds())}
+	}
+
+	if worst.Up > 0 {
+		lines = append(lines,
+			fmt.Sprintf("Worst hour: %s (%v up)", worst.Hour.Format("15:04"), worst.Up.Round(time.Millisecond)),
+			"Up per hour: "+renderHistogram(t.session.styles, hourly))
+	}
+
+	return lines
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestStatsTab_WindowSelection(t *testing.T) {
+	now := time.Now()
+	s, _, _ := newTestSession(
+		domain.Event{Timestamp: now.Add(-30 * time.Minute), Type: domain.EventDisable},
+		domain.Event{Timestamp: now.Add(-3 * time.Hour), Type: domain.EventDisable},
+		domain.Event{Timestamp: now.Add(-12 * time.Hour), Type: domain.EventEnable},
+	)
+	stats := newStatsTab(s)
+
+	tests := []struct {
+		key      string
+		window   time.Duration
+		total    int
+		disables int
+	}{
+		{"left", time.Hour, 1, 1},
+		{"right", 6 * time.Hour, 2, 2},
+		{"right", 24 * time.Hour, 3, 2},
+		{"right", 24 * time.Hour, 3, 2},
+		{"left", 6 * time.Hour, 2, 2},
+	}
+
+	for _, tt := range tests {
+		updated, _ := stats.Update(keyPress(tt.key))
+		stats = updated.(statsTab)
+
+		if statsWindows[stats.window] != tt.window {
+			t.Errorf("After %q expected window %v, got %v", tt.key, tt.window, statsWindows[stats.window])
+		}
+		if stats.summary.Total != tt.total || stats.summary.Disables != tt.disables {
+			t.Errorf("Window %v: expected %d events and %d disables, got %d and %d",
+				tt.window, tt.total, tt.disables, stats.summary.Total, stats.summary.Disables)
+		}
+	}
+}
+
+func TestStatsTab_Exposure(t *testing.T) {
+	now := time.Now()
+	s, _, _ := newTestSession(
+		domain.Event{Timestamp: now.Add(-10 * time.Minute), Type: domain.EventDisable, UpDuration: 1500 * time.Millisecond},
+		domain.Event{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventDisable, UpDuration: 500 * time.Millisecond},
+	)
+	stats := newStatsTab(s)
+
+	view := stats.View(120, 40)
+	if !contains(view, "Exposure: 2s over 2 disables, longest 1.5s") {
+		t.Errorf("Expected the exposure summary, got:\n%s", view)
+	}
+	if !contains(view, "Worst hour") {
+		t.Errorf("Expected the worst hour, got:\n%s", view)
+	}
+}
@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// timelineColumns is the number of columns each band is drawn with
+const timelineColumns = 60
+
+// timelineTab draws one band per guarded interface showing when it was UP
+// or DOWN during a selectable time window
+type timelineTab struct {
+	session *session
+	window  int
+	bands   map[string][]domain.Status
+}
+
+func newTimelineTab(s *session) timelineTab {
+	t := timelineTab{session: s}
+	t.refresh()
+	return t
+}
+
+func (t timelineTab) Title() string {
+	return "Timeline"
+}
+
+func (t timelineTab) KeyBindings() []key.Binding {
+	return []key.Binding{t.session.keys.Left, t.session.keys.Right}
+}
+
+func (t timelineTab) Update(msg tea.Msg) (tab, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, t.session.keys.Left):
+			if t.window > 0 {
+				t.window--
+			}
+		case key.Matches(msg, t.session.keys.Right):
+			if t.window < len(statsWindows)-1 {
+				t.window++
+			}
+		default:
+			return t, nil
+		}
+		t.refresh()
+
+	case checkResultMsg, actionMsg:
+		t.refresh()
+	}
+
+	return t, nil
+}
+
+func (t *timelineTab) refresh() {
+	window := statsWindows[t.window]
+
+	t.bands = make(map[string][]domain.Status)
+	for _, name := range t.session.services.Config.Interfaces {
+		t.bands[name] = t.session.services.Stats.GetTimeline(t.session.ctx, name, window, timelineColumns)
+	}
+}
+
+func (t timelineTab) View(width, height int) string {
+	styles := t.session.styles
+	window := statsWindows[t.window]
+
+	names := t.session.services.Config.Interfaces
+	labelWidth := 0
+	for _, name := range names {
+		labelWidth = max(labelWidth, len(name))
+	}
+
+	lines := []string{fmt.Sprintf("Window: last %v", window), ""}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%-*s %s", labelWidth, name, renderBand(styles, t.bands[name])))
+	}
+
+	start := "-" + window.String()
+	axis := start + strings.Repeat(" ", max(timelineColumns-len(start)-len("now"), 1)) + "now"
+	lines = append(lines,
+		fmt.Sprintf("%-*s %s", labelWidth, "", styles.Timestamp.Render(axis)),
+		"",
+		fmt.Sprintf("%s UP  %s DOWN  %s unknown",
+			styles.StatusUp.Render("█"), styles.StatusDown.Render("▁"), styles.StatusUnknown.Render("·")),
+	)
+
+	box := styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderBand draws a timeline as a single line, one cell per column
+func renderBand(styles Styles, timeline []domain.Status) string {
+	var b strings.Builder
+	for _, status := range timeline {
+		switch status {
+		case domain.StatusUp:
+			b.WriteString(styles.StatusUp.Render("█"))
+		case domain.StatusDown:
+			b.WriteString(styles.StatusDown.Render("▁"))
+		default:
+			b.WriteString(styles.StatusUnknown.Render("·"))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestTimelineTab_ShowsTransitions(t *testing.T) {
+	now := time.Now()
+	s, _, _ := newTestSession(
+		domain.Event{Timestamp: now.Add(-50 * time.Minute), Type: domain.EventCheck, Interface: "awdl0", Status: domain.StatusDown},
+		domain.Event{Timestamp: now.Add(-20 * time.Minute), Type: domain.EventCheck, Interface: "awdl0", Status: domain.StatusUp},
+	)
+	timeline := newTimelineTab(s)
+
+	band := timeline.bands["awdl0"]
+	if len(band) != timelineColumns {
+		t.Fatalf("Expected %d columns, got %d", timelineColumns, len(band))
+	}
+	if band[0] != domain.StatusUnknown || band[20] != domain.StatusDown || band[timelineColumns-1] != domain.StatusUp {
+		t.Errorf("Unexpected band %v", band)
+	}
+
+	view := timeline.View(120, 30)
+	for _, want := range []string{"awdl0", "Window: last 1h0m0s", "-1h0m0s", "now"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q", want)
+		}
+	}
+}
+
+func TestTimelineTab_WindowSelection(t *testing.T) {
+	s, _, _ := newTestSession()
+	timeline := newTimelineTab(s)
+
+	for _, k := range []string{"right", "right", "right"} {
+		updated, _ := timeline.Update(keyPress(k))
+		timeline = updated.(timelineTab)
+	}
+	if statsWindows[timeline.window] != 24*time.Hour {
+		t.Errorf("Expected the longest window, got %v", statsWindows[timeline.window])
+	}
+
+	updated, _ := timeline.Update(keyPress("left"))
+	timeline = updated.(timelineTab)
+	if statsWindows[timeline.window] != 6*time.Hour {
+		t.Errorf("Expected 6h window, got %v", statsWindows[timeline.window])
+	}
+}
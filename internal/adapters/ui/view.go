@@ -1,10 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
 func (m Model) View() string {
@@ -13,117 +17,173 @@ func (m Model) View() string {
 	}
 
 	header := m.renderHeader()
+	tabBar := m.renderTabBar()
 	footer := m.renderFooter()
 
-	headerH := lipgloss.Height(header)
-	footerH := lipgloss.Height(footer)
-	contentH := m.height - headerH - footerH
-
-	if contentH < 0 {
-		contentH = 0
-	}
-
 	var content string
-	if m.showLogs {
-		m.viewport.Width = m.width - m.styles.Logs.GetHorizontalFrameSize()
-		m.viewport.Height = contentH - m.styles.Logs.GetVerticalFrameSize()
-		content = m.styles.Logs.Render(m.viewport.View())
+	if m.help.ShowAll {
+		content = m.renderHelp(m.contentHeight())
 	} else {
-		content = m.renderDashboard(contentH)
+		content = m.tabs[m.active].View(m.width, m.contentHeight())
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, header, content, footer)
+	return lipgloss.JoinVertical(lipgloss.Left, header, tabBar, content, footer)
 }
 
-func (m Model) renderHeader() string {
-	status := " MONITORING "
-	style := m.styles.StatusUp
-	if !m.monitoring {
-		status = " PAUSED "
-		style = m.styles.StatusDown
+// contentHeight is the height left for the active tab
+func (m Model) contentHeight() int {
+	h := m.height - lipgloss.Height(m.renderHeader()) - lipgloss.Height(m.renderTabBar()) - lipgloss.Height(m.renderFooter())
+	if h < 0 {
+		return 0
 	}
+	return h
+}
 
-	awdl0Status := " UNKNOWN "
-	awdl0Style := m.styles.StatusUnknown
-	if m.awdl0Status == "UP" {
-		awdl0Status = " ENABLED "
-		awdl0Style = m.styles.StatusUp
-	} else if m.awdl0Status == "DOWN" {
-		awdl0Status = " DISABLED "
-		awdl0Style = m.styles.StatusDown
+// renderInterfaceStatus labels the primary interface's status. An Unknown
+// status tells apart why the check failed.
+func renderInterfaceStatus(styles Styles, status domain.Status, checkErr error) (string, lipgloss.Style) {
+	switch {
+	case status == domain.StatusUp:
+		return " ENABLED ", styles.StatusUp
+	case status == domain.StatusDown:
+		return " DISABLED ", styles.StatusDown
+	case status == domain.StatusAbsent:
+		return " ABSENT ", styles.StatusUnknown
+	case errors.Is(checkErr, domain.ErrPermissionDenied):
+		return " NO PERMISSION ", styles.Error
+	case errors.Is(checkErr, domain.ErrCommandFailed):
+		return " CHECK FAILED ", styles.Error
 	}
-
-	content := m.styles.Header.Render(" AWDL0 Disabler ") + " " + style.Render(status) + " " + awdl0Style.Render(awdl0Status) +
-		fmt.Sprintf(" Poll: %v (↑/↓: Adjust)", m.services.Config.PollingInterval)
-
-	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, content)
+	return " UNKNOWN ", styles.StatusUnknown
 }
 
-func (m Model) renderDashboard(availableHeight int) string {
-	maxCount := 0
-	for _, b := range m.buckets {
-		if b.Count > maxCount {
-			maxCount = b.Count
-		}
-	}
-	if maxCount == 0 {
-		maxCount = 1
+func (m Model) renderHeader() string {
+	styles := m.session.styles
+
+	status := " MONITORING "
+	style := styles.StatusUp
+	switch {
+	case !m.session.monitoring:
+		status = " PAUSED "
+		style = styles.StatusDown
+	case m.session.services.Monitor.ObserveOnly():
+		status = " OBSERVE ONLY "
+		style = styles.StatusUnknown
 	}
 
-	var bars []string
+	awdl0Status, awdl0Style := renderInterfaceStatus(styles, m.session.awdl0Status, m.session.checkErr)
 
-	blocks := []string{" ", " ", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	content := styles.Header.Render(" AWDL0 Disabler ") + " " + style.Render(status) + " " + awdl0Style.Render(awdl0Status)
 
-	for _, b := range m.buckets {
-		height := 0
-		if b.Count <= 0 {
-			bars = append(bars, m.styles.Bar.Render(blocks[0]))
-			continue
-		}
+	if m.session.helperDown {
+		content += " " + styles.Error.Render(" HELPER DOWN ")
+	}
 
-		height = int(float64(b.Count) / float64(maxCount) * 8.0)
-		if height > 8 {
-			height = 8
-		}
-		if height < 1 {
-			height = 1
-		}
+	if flapping := m.session.services.Monitor.Flapping(); len(flapping) > 0 {
+		content += " " + styles.StatusDown.Render(" FLAPPING "+strings.Join(flapping, ", ")+" ")
+	}
 
-		bars = append(bars, m.styles.Bar.Render(blocks[height]))
+	if snoozes := m.session.services.Monitor.Snoozes(); len(snoozes) > 0 {
+		// Snoozes are sorted, so the first one is the next to end
+		next := snoozes[0]
+		label := fmt.Sprintf(" ALLOWED %s %s ", next.Interface, formatCountdown(time.Until(next.Until)))
+		if len(snoozes) > 1 {
+			label += fmt.Sprintf("+%d ", len(snoozes)-1)
+		}
+		content += " " + styles.StatusUnknown.Render(label)
 	}
 
-	graph := strings.Join(bars, "")
+	if schedule, ok := m.session.services.Monitor.Schedule(); ok {
+		content += " " + renderSchedule(styles, schedule)
+	}
 
-	stats := fmt.Sprintf("Last Hour Activity: %d buckets", len(m.buckets))
+	configured := m.session.services.Config.PollingInterval
+	if effective := m.session.services.Monitor.PollInterval(); effective != configured || m.session.services.Config.AdaptivePolling {
+		content += fmt.Sprintf(" Poll: %v (set %v)", effective, configured)
+	} else {
+		content += fmt.Sprintf(" Poll: %v", configured)
+	}
 
-	dashboardContent := lipgloss.JoinVertical(lipgloss.Center, stats, "\n", graph)
+	if m.session.delayedTicks > 0 || m.session.skippedTicks > 0 {
+		content += " " + styles.StatusUnknown.Render(
+			fmt.Sprintf(" SLOW CHECKS %d delayed, %d skipped ", m.session.delayedTicks, m.session.skippedTicks))
+	}
 
-	box := m.styles.Dashboard.Render(dashboardContent)
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, content)
+}
 
-	if m.awdl0Status == "DOWN" {
-		sideEffects := []string{
-			"• AirDrop: Disabled",
-			"• AirPlay & Sidecar: Impacted",
-			"• Continuity & Handoff: Interrupted",
-			"• Watch Unlock: Disabled",
+func (m Model) renderTabBar() string {
+	var tabs []string
+	for i, t := range m.tabs {
+		label := fmt.Sprintf(" %d %s ", i+1, t.Title())
+		if i == m.active {
+			tabs = append(tabs, m.session.styles.TabActive.Render(label))
+		} else {
+			tabs = append(tabs, m.session.styles.TabInactive.Render(label))
 		}
-		sideEffectsView := m.styles.SideEffects.Render(strings.Join(sideEffects, "  "))
-		box = lipgloss.JoinVertical(lipgloss.Center, box, sideEffectsView)
 	}
 
-	return lipgloss.Place(m.width, availableHeight, lipgloss.Center, lipgloss.Center, box)
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
 }
 
 func (m Model) renderFooter() string {
 	if m.statusMsg != "" {
-		style := m.styles.Footer
+		style := m.session.styles.Footer
 
 		if strings.HasPrefix(m.statusMsg, "Error") {
-			style = style.Foreground(lipgloss.Color("196")) // Red
+			style = style.Foreground(m.session.styles.Error.GetForeground())
 		}
 		return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, style.Render(m.statusMsg))
 	}
 
-	help := "Space: Pause/Resume • L: Logs • E: Toggle awdl0 • ↑: Slower • ↓: Faster • Q: Quit"
-	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.styles.Footer.Render(help))
+	short := m.help
+	short.ShowAll = false
+	short.Styles = m.session.styles.helpStyles()
+	return lipgloss.PlaceHorizontal(m.width, lipgloss.Center, m.session.styles.Footer.Render(short.View(m.keyMap())))
+}
+
+// renderHelp shows every binding of the active tab over the tab content
+func (m Model) renderHelp(availableHeight int) string {
+	title := fmt.Sprintf("%s keys", m.tabs[m.active].Title())
+	full := m.help
+	full.Styles = m.session.styles.helpStyles()
+	fullView := full.FullHelpView(m.keyMap().FullHelp())
+
+	box := m.session.styles.Dashboard.Render(lipgloss.JoinVertical(lipgloss.Left, title, "", fullView))
+
+	return lipgloss.Place(m.width, availableHeight, lipgloss.Center, lipgloss.Center, box)
+}
+
+// renderSchedule shows the active mode, the rule behind it and the next
+// transition
+func renderSchedule(styles Styles, state domain.ScheduleState) string {
+	style := styles.StatusUp
+	if state.Mode == domain.ModeObserve {
+		style = styles.StatusUnknown
+	}
+
+	label := " " + strings.ToUpper(string(state.Mode)) + " "
+	if state.Rule != "" {
+		label += state.Rule + " "
+	}
+
+	next := ""
+	if !state.Next.IsZero() {
+		next = fmt.Sprintf(" %s at %s", state.NextMode, state.Next.Format("Mon 15:04"))
+	}
+
+	return style.Render(label) + next
+}
+
+// formatCountdown renders a remaining duration as h:mm:ss or m:ss
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d.Round(time.Second) / time.Second)
+	h, m, sec := total/3600, total/60%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, sec)
+	}
+	return fmt.Sprintf("%d:%02d", m, sec)
 }
@@ -0,0 +1,369 @@
+package domain
+
+import (
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Config represents the user configuration
+type Config struct {
+	PollingInterval time.Duration `json:"polling_interval"`
+	// AdaptivePolling polls as fast as allowed right after a reactivation and
+	// slows down exponentially towards AdaptiveCeiling while it is quiet
+	AdaptivePolling bool          `json:"adaptive_polling,omitempty"`
+	AdaptiveCeiling time.Duration `json:"adaptive_ceiling,omitempty"`
+	// PollJitter adds a random delay of up to this long to every wait, so
+	// polls do not line up with other periodic work
+	PollJitter time.Duration `json:"poll_jitter,omitempty"`
+
+	// Interfaces are the interfaces kept down
+	Interfaces []string `json:"interfaces"`
+	// NetworkBackend selects how interfaces are checked and changed. It is
+	// read at startup.
+	NetworkBackend string `json:"network_backend,omitempty"`
+	// IfconfigPath and SudoPath override the binaries the shell backend
+	// runs, empty means looking them up in PATH. The ip backend uses SudoPath
+	// too.
+	IfconfigPath string `json:"ifconfig_path,omitempty"`
+	SudoPath     string `json:"sudo_path,omitempty"`
+	// OperationTimeout bounds every single check or change of an interface
+	OperationTimeout time.Duration `json:"operation_timeout"`
+
+	// DebounceReadings is how many consecutive UP readings are needed before
+	// an interface is disabled, DebounceDuration how long it must have been
+	// UP. Zero values disable on the first UP reading.
+	DebounceReadings int           `json:"debounce_readings,omitempty"`
+	DebounceDuration time.Duration `json:"debounce_duration,omitempty"`
+	// HysteresisReadings is how many DOWN readings an UP streak survives
+	HysteresisReadings int `json:"hysteresis_readings,omitempty"`
+
+	// FlapThreshold is how many disables within FlapWindow are tolerated
+	// before an interface counts as flapping, zero turns detection off.
+	// FlapStrategy is what happens then.
+	FlapThreshold int           `json:"flap_threshold"`
+	FlapWindow    time.Duration `json:"flap_window"`
+	FlapStrategy  string        `json:"flap_strategy"`
+
+	// SnoozeShort and SnoozeLong are the durations of the TUI allow presets
+	SnoozeShort time.Duration `json:"snooze_short"`
+	SnoozeLong  time.Duration `json:"snooze_long"`
+
+	// Schedule limits enforcement to certain hours, nil always enforces
+	Schedule *Schedule `json:"schedule,omitempty"`
+	// ProcessRules switch the mode while certain apps run, overriding the
+	// schedule
+	ProcessRules []ProcessRule `json:"process_rules,omitempty"`
+
+	// KeyBindings overrides the keys of TUI actions, e.g. {"pause": ["p"]}
+	KeyBindings map[string][]string `json:"key_bindings,omitempty"`
+
+	// Theme selects the TUI palette, ThemeAuto picks dark or light from the
+	// terminal background
+	Theme string `json:"theme,omitempty"`
+	// Styles overrides individual TUI styles by name, e.g. "header"
+	Styles map[string]StyleOverride `json:"styles,omitempty"`
+}
+
+// StyleOverride changes individual attributes of a TUI style. Empty or nil
+// fields keep the theme's value.
+type StyleOverride struct {
+	Foreground string `json:"foreground,omitempty"`
+	Background string `json:"background,omitempty"`
+	Bold       *bool  `json:"bold,omitempty"`
+	Italic     *bool  `json:"italic,omitempty"`
+	Underline  *bool  `json:"underline,omitempty"`
+}
+
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+const (
+	// FlapBackoff disables a flapping interface less and less often
+	FlapBackoff = "backoff"
+	// FlapAggressive keeps disabling it, polling as fast as allowed
+	FlapAggressive = "aggressive"
+	// FlapStop stops guarding it until it calms down
+	FlapStop = "stop"
+)
+
+const (
+	// BackendShell runs ifconfig
+	BackendShell = "shell"
+	// BackendIoctl reads and sets interface flags in-process, falling back
+	// to BackendShell when that fails
+	BackendIoctl = "ioctl"
+	// BackendIP runs iproute2's ip, on Linux
+	BackendIP = "ip"
+)
+
+// NetworkBackends lists every valid value of Config.NetworkBackend
+var NetworkBackends = []string{BackendShell, BackendIoctl, BackendIP}
+
+// FlapStrategies lists every valid value of Config.FlapStrategy
+var FlapStrategies = []string{FlapBackoff, FlapAggressive, FlapStop}
+
+// Themes lists every valid value of Config.Theme
+var Themes = []string{ThemeAuto, ThemeDark, ThemeLight, ThemeHighContrast, ThemeMonochrome}
+
+const (
+	MinPollingInterval = 500 * time.Millisecond
+	MaxPollingInterval = 60 * time.Second
+
+	MaxSnooze = 24 * time.Hour
+
+	MinOperationTimeout = 100 * time.Millisecond
+	MaxOperationTimeout = 60 * time.Second
+
+	MaxDebounceReadings = 100
+	MaxDebounceDuration = 10 * time.Minute
+
+	MaxFlapThreshold = 1000
+	MaxFlapWindow    = 24 * time.Hour
+)
+
+// DefaultConfig returns the configuration used when none has been saved
+func DefaultConfig() *Config {
+	return &Config{
+		PollingInterval:  1 * time.Second,
+		AdaptiveCeiling:  10 * time.Second,
+		Interfaces:       []string{"awdl0"},
+		NetworkBackend:   BackendShell,
+		OperationTimeout: 5 * time.Second,
+		SnoozeShort:      5 * time.Minute,
+		SnoozeLong:       30 * time.Minute,
+		FlapThreshold:    30,
+		FlapWindow:       10 * time.Minute,
+		FlapStrategy:     FlapBackoff,
+		Theme:            ThemeAuto,
+	}
+}
+
+// Clamp ensures the configuration values are within valid ranges
+func (c *Config) Clamp() {
+	if c.PollingInterval < MinPollingInterval {
+		c.PollingInterval = MinPollingInterval
+	}
+
+	if c.PollingInterval > MaxPollingInterval {
+		c.PollingInterval = MaxPollingInterval
+	}
+
+	if c.NetworkBackend == "" {
+		c.NetworkBackend = BackendShell
+	}
+
+	if c.OperationTimeout == 0 {
+		c.OperationTimeout = DefaultConfig().OperationTimeout
+	}
+
+	if c.FlapStrategy == "" {
+		c.FlapStrategy = FlapBackoff
+	}
+
+	if c.Theme == "" {
+		c.Theme = ThemeAuto
+	}
+}
+
+// Clone returns a deep copy, which can be changed without affecting c
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Interfaces = slices.Clone(c.Interfaces)
+
+	if c.Schedule != nil {
+		schedule := *c.Schedule
+		schedule.Rules = slices.Clone(c.Schedule.Rules)
+		for i := range schedule.Rules {
+			schedule.Rules[i].Days = slices.Clone(schedule.Rules[i].Days)
+		}
+		clone.Schedule = &schedule
+	}
+
+	clone.ProcessRules = slices.Clone(c.ProcessRules)
+	for i := range clone.ProcessRules {
+		clone.ProcessRules[i].Processes = slices.Clone(clone.ProcessRules[i].Processes)
+	}
+
+	clone.KeyBindings = maps.Clone(c.KeyBindings)
+	for action, keys := range clone.KeyBindings {
+		clone.KeyBindings[action] = slices.Clone(keys)
+	}
+	clone.Styles = maps.Clone(c.Styles)
+
+	return &clone
+}
+
+// FieldError describes why a single configuration field is invalid
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every invalid field of a configuration
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Field returns the message for the given field, if it is invalid
+func (e ValidationErrors) Field(name string) (string, bool) {
+	for _, fe := range e {
+		if fe.Field == name {
+			return fe.Message, true
+		}
+	}
+	return "", false
+}
+
+// Validate reports every field that is outside its valid range. Unlike Clamp
+// it does not change the configuration.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+
+	if c.PollingInterval < MinPollingInterval || c.PollingInterval > MaxPollingInterval {
+		errs = append(errs, FieldError{
+			Field:   "polling_interval",
+			Message: fmt.Sprintf("must be between %v and %v", MinPollingInterval, MaxPollingInterval),
+		})
+	}
+
+	if c.AdaptivePolling && (c.AdaptiveCeiling < MinPollingInterval || c.AdaptiveCeiling > MaxPollingInterval) {
+		errs = append(errs, FieldError{
+			Field:   "adaptive_ceiling",
+			Message: fmt.Sprintf("must be between %v and %v", MinPollingInterval, MaxPollingInterval),
+		})
+	}
+
+	if c.PollJitter < 0 || c.PollJitter > MaxPollingInterval {
+		errs = append(errs, FieldError{Field: "poll_jitter", Message: fmt.Sprintf("must be between 0s and %v", MaxPollingInterval)})
+	}
+
+	if len(c.Interfaces) == 0 {
+		errs = append(errs, FieldError{Field: "interfaces", Message: "at least one interface is required"})
+	}
+	for _, name := range c.Interfaces {
+		if !ValidInterfaceName(name) {
+			errs = append(errs, FieldError{Field: "interfaces", Message: fmt.Sprintf("%q is not a valid interface name", name)})
+			break
+		}
+	}
+
+	if !contains(NetworkBackends, c.NetworkBackend) {
+		errs = append(errs, FieldError{
+			Field:   "network_backend",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(NetworkBackends, ", ")),
+		})
+	} else if c.NetworkBackend == BackendIP && runtime.GOOS == "darwin" {
+		errs = append(errs, FieldError{Field: "network_backend", Message: "ip is only available on Linux"})
+	}
+
+	if c.OperationTimeout < MinOperationTimeout || c.OperationTimeout > MaxOperationTimeout {
+		errs = append(errs, FieldError{
+			Field:   "operation_timeout",
+			Message: fmt.Sprintf("must be between %v and %v", MinOperationTimeout, MaxOperationTimeout),
+		})
+	}
+
+	for _, f := range []struct {
+		name  string
+		value int
+	}{{"debounce_readings", c.DebounceReadings}, {"hysteresis_readings", c.HysteresisReadings}} {
+		if f.value < 0 || f.value > MaxDebounceReadings {
+			errs = append(errs, FieldError{Field: f.name, Message: fmt.Sprintf("must be between 0 and %d", MaxDebounceReadings)})
+		}
+	}
+
+	if c.DebounceDuration < 0 || c.DebounceDuration > MaxDebounceDuration {
+		errs = append(errs, FieldError{Field: "debounce_duration", Message: fmt.Sprintf("must be between 0s and %v", MaxDebounceDuration)})
+	}
+
+	if c.FlapThreshold < 0 || c.FlapThreshold > MaxFlapThreshold {
+		errs = append(errs, FieldError{Field: "flap_threshold", Message: fmt.Sprintf("must be between 0 and %d", MaxFlapThreshold)})
+	}
+	if c.FlapThreshold > 0 && (c.FlapWindow <= 0 || c.FlapWindow > MaxFlapWindow) {
+		errs = append(errs, FieldError{Field: "flap_window", Message: fmt.Sprintf("must be greater than 0s and at most %v", MaxFlapWindow)})
+	}
+	if !contains(FlapStrategies, c.FlapStrategy) {
+		errs = append(errs, FieldError{
+			Field:   "flap_strategy",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(FlapStrategies, ", ")),
+		})
+	}
+
+	for _, f := range []struct {
+		name  string
+		value time.Duration
+	}{{"snooze_short", c.SnoozeShort}, {"snooze_long", c.SnoozeLong}} {
+		if f.value <= 0 || f.value > MaxSnooze {
+			errs = append(errs, FieldError{Field: f.name, Message: fmt.Sprintf("must be greater than 0s and at most %v", MaxSnooze)})
+		}
+	}
+
+	if c.Schedule != nil {
+		if err := c.Schedule.Validate(); err != nil {
+			errs = append(errs, FieldError{Field: "schedule", Message: err.Error()})
+		}
+	}
+
+	for _, rule := range c.ProcessRules {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, FieldError{Field: "process_rules", Message: err.Error()})
+			break
+		}
+	}
+
+	if !contains(Themes, c.Theme) {
+		errs = append(errs, FieldError{
+			Field:   "theme",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(Themes, ", ")),
+		})
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// ValidInterfaceName reports whether name looks like a network interface
+// name, such as awdl0 or en0
+func ValidInterfaceName(name string) bool {
+	if name == "" || len(name) > 15 {
+		return false
+	}
+
+	for _, r := range name {
+		isAlnum := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+		if !isAlnum && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
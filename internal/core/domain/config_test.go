@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	// macOS has no iproute2
+	ipBackend := *domain.DefaultConfig()
+	ipBackend.NetworkBackend = domain.BackendIP
+	var ipInvalid []string
+	if runtime.GOOS == "darwin" {
+		ipInvalid = []string{"network_backend"}
+	}
+
+	tests := []struct {
+		name    string
+		config  domain.Config
+		invalid []string
+	}{
+		{
+			name:   "Defaults",
+			config: *domain.DefaultConfig(),
+		},
+		{
+			name:    "Polling Too Fast",
+			config:  domain.Config{PollingInterval: 100 * time.Millisecond, Theme: domain.ThemeDark},
+			invalid: []string{"polling_interval"},
+		},
+		{
+			name:    "Polling Too Slow",
+			config:  domain.Config{PollingInterval: 2 * time.Minute, Theme: domain.ThemeDark},
+			invalid: []string{"polling_interval"},
+		},
+		{
+			name:    "Negative Jitter",
+			config:  domain.Config{PollingInterval: time.Second, Theme: domain.ThemeDark, PollJitter: -time.Second},
+			invalid: []string{"poll_jitter"},
+		},
+		{
+			name:    "Unknown Theme",
+			config:  domain.Config{PollingInterval: time.Second, Theme: "neon"},
+			invalid: []string{"theme"},
+		},
+		{
+			name:    "Unknown Network Backend",
+			config:  domain.Config{PollingInterval: time.Second, Theme: domain.ThemeDark, NetworkBackend: "carrier-pigeon"},
+			invalid: []string{"network_backend"},
+		},
+		{
+			name:    "IP Network Backend",
+			config:  ipBackend,
+			invalid: ipInvalid,
+		},
+		{
+			name:    "Operation Timeout Too Long",
+			config:  domain.Config{PollingInterval: time.Second, Theme: domain.ThemeDark, OperationTimeout: 2 * time.Minute},
+			invalid: []string{"operation_timeout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+
+			if len(tt.invalid) == 0 {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+
+			var verrs domain.ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			for _, field := range tt.invalid {
+				if _, ok := verrs.Field(field); !ok {
+					t.Errorf("Expected %s to be invalid, got %v", field, verrs)
+				}
+			}
+		})
+	}
+}
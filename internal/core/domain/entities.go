@@ -9,15 +9,26 @@ const (
 	StatusUp      Status = "UP"
 	StatusDown    Status = "DOWN"
 	StatusUnknown Status = "Unknown"
+	// StatusAbsent means the interface does not exist
+	StatusAbsent Status = "Absent"
 )
 
 // EventType distinguishes between different system actions
 type EventType string
 
 const (
-	EventDisable EventType = "Disable"
-	EventEnable  EventType = "Enable"
-	EventCheck   EventType = "Check"
+	EventDisable      EventType = "Disable"
+	EventEnable       EventType = "Enable"
+	EventCheck        EventType = "Check"
+	EventSnoozeStart  EventType = "SnoozeStart"
+	EventSnoozeEnd    EventType = "SnoozeEnd"
+	EventSchedule     EventType = "Schedule"
+	EventRule         EventType = "Rule"
+	EventFlapDetected EventType = "FlapDetected"
+	EventFlapCleared  EventType = "FlapCleared"
+	// EventWouldDisable is what observe-only mode records instead of a
+	// Disable
+	EventWouldDisable EventType = "WouldDisable"
 )
 
 // Event represents a system action occurred at a specific time
@@ -25,27 +36,35 @@ type Event struct {
 	Timestamp time.Time
 	Type      EventType
 	Message   string
+	// Interface is the interface the event is about, if any
+	Interface string
+	// UpDuration is how long the interface was seen UP before a disable was
+	// verified, from its first UP reading
+	UpDuration time.Duration
+	// Status is the state a Check event observed
+	Status Status
+	// Cursor is where the logger stored the event. Its Day is zero when the
+	// event could not be logged.
+	Cursor HistoryCursor
 }
 
-// Config represents the user configuration
-type Config struct {
-	PollingInterval time.Duration `json:"polling_interval"`
+// Snooze allows an interface to stay up until the given time
+type Snooze struct {
+	Interface string    `json:"interface"`
+	Until     time.Time `json:"until"`
 }
 
-const (
-	MinPollingInterval = 500 * time.Millisecond
-	MaxPollingInterval = 60 * time.Second
-)
-
-// Clamp ensures the configuration values are within valid ranges
-func (c *Config) Clamp() {
-	if c.PollingInterval < MinPollingInterval {
-		c.PollingInterval = MinPollingInterval
-	}
+// HistoryCursor identifies a logged event by its day and its position
+// within that day's log
+type HistoryCursor struct {
+	Day   time.Time
+	Index int
+}
 
-	if c.PollingInterval > MaxPollingInterval {
-		c.PollingInterval = MaxPollingInterval
-	}
+// HistoryEntry is a logged event together with its position in the history
+type HistoryEntry struct {
+	Cursor HistoryCursor
+	Event  Event
 }
 
 // Bucket represents a time slot in the histogram
@@ -53,3 +72,72 @@ type Bucket struct {
 	Label string // e.g., "10:05"
 	Count int
 }
+
+// Summary aggregates the events of a time window
+type Summary struct {
+	Total    int
+	Disables int
+	Enables  int
+	// WouldDisables counts the disables skipped in observe-only mode
+	WouldDisables int
+	Last          time.Time
+}
+
+// Exposure sums how long guarded interfaces were seen UP before they were
+// disabled
+type Exposure struct {
+	// Hours holds the time spent UP per clock hour, oldest first
+	Hours    []HourlyExposure
+	Total    time.Duration
+	Disables int
+	// Longest is the longest single UP period
+	Longest time.Duration
+}
+
+// HourlyExposure is the time spent UP within one clock hour
+type HourlyExposure struct {
+	Hour time.Time
+	Up   time.Duration
+}
+
+// Measurement is the result of timing how quickly an interface comes back UP
+// after being disabled
+type Measurement struct {
+	Interface string `json:"interface"`
+	// Samples are the time-to-reactivate of every trial that came back
+	Samples  []time.Duration `json:"samples_ns"`
+	TimedOut int             `json:"timed_out"`
+
+	Min    time.Duration `json:"min_ns"`
+	Median time.Duration `json:"median_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	Max    time.Duration `json:"max_ns"`
+}
+
+// Interface describes a network interface as reported by the system
+type Interface struct {
+	Name string
+	// Flags are the interface flags, e.g. UP, RUNNING or PROMISC
+	Flags []string
+	MTU   int
+	// Ether is the hardware address, empty if it has none
+	Ether string
+	Inet  []string
+	// Inet6 addresses are listed without their zone
+	Inet6 []string
+	// Status is UP when the UP flag is set, DOWN otherwise
+	Status Status
+	// LinkStatus is the link state reported next to the media, e.g. "active"
+	LinkStatus string
+	Media      string
+}
+
+// HasFlag reports whether the interface has the given flag set
+func (i Interface) HasFlag(flag string) bool {
+	for _, f := range i.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
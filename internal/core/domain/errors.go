@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInterfaceNotFound means the interface does not exist on this machine
+	ErrInterfaceNotFound = errors.New("interface not found")
+	// ErrPermissionDenied means the command needs more privileges
+	ErrPermissionDenied = errors.New("permission denied")
+	// ErrCommandFailed matches every CommandError
+	ErrCommandFailed = errors.New("command failed")
+	// ErrObserveOnly means interfaces cannot be changed in observe-only mode
+	ErrObserveOnly = errors.New("observe-only mode, interfaces are not changed")
+	// ErrHelperUnavailable means the privileged helper that changes
+	// interfaces has exited and could not be started again
+	ErrHelperUnavailable = errors.New("privileged helper is not running")
+)
+
+// CommandError describes a command that exited unsuccessfully. Kind is
+// ErrInterfaceNotFound or ErrPermissionDenied when the failure was
+// recognized, and matches with errors.Is as well as ErrCommandFailed.
+type CommandError struct {
+	Command  string
+	ExitCode int
+	Stderr   string
+	Kind     error
+}
+
+func (e *CommandError) Error() string {
+	msg := fmt.Sprintf("%s: exit code %d", e.Command, e.ExitCode)
+	if e.Kind != nil {
+		msg = fmt.Sprintf("%s: %v (exit code %d)", e.Command, e.Kind, e.ExitCode)
+	}
+	if stderr := strings.TrimSpace(e.Stderr); stderr != "" {
+		msg += ": " + stderr
+	}
+	return msg
+}
+
+func (e *CommandError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{ErrCommandFailed}
+	}
+	return []error{e.Kind, ErrCommandFailed}
+}
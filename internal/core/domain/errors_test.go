@@ -0,0 +1,36 @@
+package domain_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestCommandError_Matches(t *testing.T) {
+	notFound := &domain.CommandError{Command: "ifconfig awdl9", ExitCode: 1, Stderr: "ifconfig: interface awdl9 does not exist\n", Kind: domain.ErrInterfaceNotFound}
+	wrapped := fmt.Errorf("checking: %w", notFound)
+
+	if !errors.Is(wrapped, domain.ErrInterfaceNotFound) || !errors.Is(wrapped, domain.ErrCommandFailed) {
+		t.Error("Expected the error to match both its kind and domain.ErrCommandFailed")
+	}
+	if errors.Is(wrapped, domain.ErrPermissionDenied) {
+		t.Error("Expected the error not to match domain.ErrPermissionDenied")
+	}
+
+	want := "ifconfig awdl9: interface not found (exit code 1): ifconfig: interface awdl9 does not exist"
+	if notFound.Error() != want {
+		t.Errorf("Expected %q, got %q", want, notFound.Error())
+	}
+
+	var cmdErr *domain.CommandError
+	if !errors.As(wrapped, &cmdErr) || cmdErr.ExitCode != 1 {
+		t.Error("Expected the exit code to be reachable with errors.As")
+	}
+
+	failed := &domain.CommandError{Command: "ifconfig awdl0 down", ExitCode: 2}
+	if !errors.Is(failed, domain.ErrCommandFailed) || failed.Error() != "ifconfig awdl0 down: exit code 2" {
+		t.Errorf("Unexpected unclassified error %q", failed.Error())
+	}
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ProcessRule switches the mode while any of its processes is running. Rules
+// take precedence over the schedule, and the first matching rule wins.
+type ProcessRule struct {
+	Name string `json:"name"`
+	// Processes are executable names, such as "zoom.us", compared without
+	// regard to case
+	Processes []string `json:"processes"`
+	Mode      Mode     `json:"mode"`
+}
+
+// Validate reports the first problem found in the rule
+func (r ProcessRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("a name is required")
+	}
+	if len(r.Processes) == 0 {
+		return fmt.Errorf("%s: at least one process is required", r.Name)
+	}
+	if !validMode(r.Mode) {
+		return fmt.Errorf("%s: mode must be %s or %s", r.Name, ModeEnforce, ModeObserve)
+	}
+	return nil
+}
+
+// Match returns the first of the rule's processes found among the running
+// ones
+func (r ProcessRule) Match(running []string) (string, bool) {
+	for _, want := range r.Processes {
+		for _, name := range running {
+			if strings.EqualFold(name, want) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
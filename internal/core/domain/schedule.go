@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Mode is what the monitor does with UP interfaces
+type Mode string
+
+const (
+	// ModeEnforce disables guarded interfaces as soon as they come UP
+	ModeEnforce Mode = "enforce"
+	// ModeObserve leaves guarded interfaces alone
+	ModeObserve Mode = "observe"
+)
+
+// Schedule switches between modes on a weekly cycle. Outside of every rule
+// the Default mode applies.
+type Schedule struct {
+	// Timezone is an IANA name such as "Europe/Berlin", empty means local time
+	Timezone string         `json:"timezone,omitempty"`
+	Default  Mode           `json:"default"`
+	Rules    []ScheduleRule `json:"rules"`
+}
+
+// ScheduleRule applies a mode on the given days between Start and End, both
+// "15:04". A window whose End is not after its Start runs past midnight into
+// the next day; equal times cover the whole day.
+type ScheduleRule struct {
+	Mode Mode `json:"mode"`
+	// Days are day names or ranges, e.g. ["mon-fri"] or ["sat", "sun"]
+	Days  []string `json:"days"`
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+}
+
+// ScheduleState is the schedule as seen at one instant
+type ScheduleState struct {
+	Mode Mode
+	// Rule describes the rule in effect, empty when the default mode applies
+	Rule string
+	// Next is when the mode changes next, zero if it never does
+	Next     time.Time
+	NextMode Mode
+}
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// Location returns the schedule's timezone
+func (s *Schedule) Location() (*time.Location, error) {
+	if s.Timezone == "" || s.Timezone == "Local" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// Validate reports the first problem found in the schedule
+func (s *Schedule) Validate() error {
+	if _, err := s.Location(); err != nil {
+		return fmt.Errorf("unknown timezone %q", s.Timezone)
+	}
+	if !validMode(s.Default) {
+		return fmt.Errorf("default mode must be %s or %s", ModeEnforce, ModeObserve)
+	}
+
+	for i, rule := range s.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("rule %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r ScheduleRule) validate() error {
+	if !validMode(r.Mode) {
+		return fmt.Errorf("mode must be %s or %s", ModeEnforce, ModeObserve)
+	}
+	if _, err := parseDays(r.Days); err != nil {
+		return err
+	}
+	if _, err := parseClock(r.Start); err != nil {
+		return err
+	}
+	if _, err := parseClock(r.End); err != nil {
+		return err
+	}
+	return nil
+}
+
+// String describes when the rule applies, e.g. "mon-fri 09:00-18:00"
+func (r ScheduleRule) String() string {
+	return fmt.Sprintf("%s %s-%s", strings.Join(r.Days, ","), r.Start, r.End)
+}
+
+func validMode(m Mode) bool {
+	return m == ModeEnforce || m == ModeObserve
+}
+
+// At returns the mode in effect at t and when it changes next. An invalid
+// schedule is evaluated as far as it can be, in local time.
+func (s *Schedule) At(t time.Time) ScheduleState {
+	loc, err := s.Location()
+	if err != nil {
+		loc = time.Local
+	}
+	t = t.In(loc)
+
+	mode, rule := s.modeAt(t)
+	state := ScheduleState{Mode: mode, Rule: rule}
+
+	// The mode can only change where a window opens or closes, and the
+	// schedule repeats every week
+	for _, boundary := range s.boundaries(t, loc) {
+		if next, _ := s.modeAt(boundary); next != mode {
+			state.Next = boundary
+			state.NextMode = next
+			break
+		}
+	}
+
+	return state
+}
+
+// modeAt returns the mode at t and the rule that set it. The first matching
+// rule wins.
+func (s *Schedule) modeAt(t time.Time) (Mode, string) {
+	for _, rule := range s.Rules {
+		// A window that opened yesterday may still be running
+		for _, offset := range []int{0, -1} {
+			start, end, ok := rule.window(t.AddDate(0, 0, offset), t.Location())
+			if ok && !t.Before(start) && t.Before(end) {
+				return rule.Mode, rule.String()
+			}
+		}
+	}
+	return s.Default, ""
+}
+
+// window returns the rule's window opening on the day of t, if it has one
+func (r ScheduleRule) window(t time.Time, loc *time.Location) (time.Time, time.Time, bool) {
+	days, err := parseDays(r.Days)
+	if err != nil || !days[t.Weekday()] {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err1 := parseClock(r.Start)
+	end, err2 := parseClock(r.End)
+	if err1 != nil || err2 != nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	y, m, d := t.Date()
+	opens := time.Date(y, m, d, 0, start, 0, 0, loc)
+	closes := time.Date(y, m, d, 0, end, 0, 0, loc)
+	if end <= start {
+		closes = time.Date(y, m, d+1, 0, end, 0, 0, loc)
+	}
+	return opens, closes, true
+}
+
+// boundaries lists every window edge within a week after t, in order
+func (s *Schedule) boundaries(t time.Time, loc *time.Location) []time.Time {
+	var edges []time.Time
+	for offset := -1; offset <= 7; offset++ {
+		day := t.AddDate(0, 0, offset)
+		for _, rule := range s.Rules {
+			if start, end, ok := rule.window(day, loc); ok {
+				edges = append(edges, start, end)
+			}
+		}
+	}
+
+	var after []time.Time
+	for _, edge := range edges {
+		if edge.After(t) {
+			after = append(after, edge)
+		}
+	}
+	sort.Slice(after, func(i, j int) bool { return after[i].Before(after[j]) })
+	return after
+}
+
+// parseDays turns names and ranges such as "mon-fri" into a weekday mask
+func parseDays(specs []string) ([7]bool, error) {
+	var days [7]bool
+	if len(specs) == 0 {
+		return days, fmt.Errorf("at least one day is required")
+	}
+
+	for _, spec := range specs {
+		from, to, isRange := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "-")
+		if !isRange {
+			to = from
+		}
+
+		first, ok1 := weekdayIndex(from)
+		last, ok2 := weekdayIndex(to)
+		if !ok1 || !ok2 {
+			return days, fmt.Errorf("unknown day %q, expected names like mon or ranges like mon-fri", spec)
+		}
+
+		for i := first; ; i = (i + 1) % 7 {
+			days[i] = true
+			if i == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func weekdayIndex(name string) (int, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	for i, day := range weekdays {
+		if strings.HasPrefix(name, day) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses "15:04" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
@@ -0,0 +1,116 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+func TestSchedule_At(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone database unavailable: %v", err)
+	}
+
+	workHours := &domain.Schedule{
+		Timezone: "Europe/Berlin",
+		Default:  domain.ModeObserve,
+		Rules: []domain.ScheduleRule{
+			{Mode: domain.ModeEnforce, Days: []string{"mon-fri"}, Start: "09:00", End: "18:00"},
+			{Mode: domain.ModeEnforce, Days: []string{"sat"}, Start: "22:00", End: "02:00"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		mode     domain.Mode
+		next     time.Time
+		nextMode domain.Mode
+	}{
+		{
+			name:     "Weekday Morning",
+			at:       time.Date(2024, 5, 6, 8, 0, 0, 0, berlin), // Monday
+			mode:     domain.ModeObserve,
+			next:     time.Date(2024, 5, 6, 9, 0, 0, 0, berlin),
+			nextMode: domain.ModeEnforce,
+		},
+		{
+			name:     "Weekday Working Hours",
+			at:       time.Date(2024, 5, 8, 12, 0, 0, 0, berlin),
+			mode:     domain.ModeEnforce,
+			next:     time.Date(2024, 5, 8, 18, 0, 0, 0, berlin),
+			nextMode: domain.ModeObserve,
+		},
+		{
+			name:     "Friday Evening Skips The Weekend",
+			at:       time.Date(2024, 5, 10, 19, 0, 0, 0, berlin),
+			mode:     domain.ModeObserve,
+			next:     time.Date(2024, 5, 11, 22, 0, 0, 0, berlin),
+			nextMode: domain.ModeEnforce,
+		},
+		{
+			name:     "Past Midnight",
+			at:       time.Date(2024, 5, 12, 1, 0, 0, 0, berlin), // Sunday
+			mode:     domain.ModeEnforce,
+			next:     time.Date(2024, 5, 12, 2, 0, 0, 0, berlin),
+			nextMode: domain.ModeObserve,
+		},
+		{
+			name:     "Other Timezone",
+			at:       time.Date(2024, 5, 6, 7, 30, 0, 0, time.UTC), // 09:30 in Berlin
+			mode:     domain.ModeEnforce,
+			next:     time.Date(2024, 5, 6, 18, 0, 0, 0, berlin),
+			nextMode: domain.ModeObserve,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := workHours.At(tt.at)
+
+			if state.Mode != tt.mode {
+				t.Errorf("Expected mode %s, got %s", tt.mode, state.Mode)
+			}
+			if !state.Next.Equal(tt.next) || state.NextMode != tt.nextMode {
+				t.Errorf("Expected %s at %v, got %s at %v", tt.nextMode, tt.next, state.NextMode, state.Next)
+			}
+		})
+	}
+}
+
+func TestSchedule_AtWithoutTransitions(t *testing.T) {
+	always := &domain.Schedule{Default: domain.ModeEnforce}
+
+	state := always.At(time.Now())
+	if state.Mode != domain.ModeEnforce || !state.Next.IsZero() {
+		t.Errorf("Expected enforcement without transitions, got %+v", state)
+	}
+}
+
+func TestSchedule_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule domain.Schedule
+		valid    bool
+	}{
+		{"Valid", domain.Schedule{Default: domain.ModeObserve, Rules: []domain.ScheduleRule{{Mode: domain.ModeEnforce, Days: []string{"Mon-Fri"}, Start: "09:00", End: "18:00"}}}, true},
+		{"Unknown Timezone", domain.Schedule{Timezone: "Mars/Olympus", Default: domain.ModeEnforce}, false},
+		{"Unknown Mode", domain.Schedule{Default: "sometimes"}, false},
+		{"Unknown Day", domain.Schedule{Default: domain.ModeObserve, Rules: []domain.ScheduleRule{{Mode: domain.ModeEnforce, Days: []string{"funday"}, Start: "09:00", End: "18:00"}}}, false},
+		{"Bad Time", domain.Schedule{Default: domain.ModeObserve, Rules: []domain.ScheduleRule{{Mode: domain.ModeEnforce, Days: []string{"mon"}, Start: "9am", End: "18:00"}}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.Validate()
+			if tt.valid && err != nil {
+				t.Errorf("Expected a valid schedule, got %v", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
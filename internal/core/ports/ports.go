@@ -1,34 +1,68 @@
 package ports
 
 import (
+	"context"
 	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
-// NetworkPort handles interaction with the operating system's network interfaces
+// NetworkPort handles interaction with the operating system's network
+// interfaces. Implementations give up when the context is done.
 type NetworkPort interface {
-	CheckInterface(name string) (domain.Status, error)
-	DisableInterface(name string) error
-	EnableInterface(name string) error
+	CheckInterface(ctx context.Context, name string) (domain.Status, error)
+	// DescribeInterface returns everything known about an interface
+	DescribeInterface(ctx context.Context, name string) (domain.Interface, error)
+	// ListInterfaces describes every interface of the system
+	ListInterfaces(ctx context.Context) ([]domain.Interface, error)
+	DisableInterface(ctx context.Context, name string) error
+	EnableInterface(ctx context.Context, name string) error
+}
+
+// AllowListPort limits which interfaces may be changed, when changes go
+// through a privileged helper
+type AllowListPort interface {
+	// AllowInterfaces replaces the interfaces that may be brought up or down
+	AllowInterfaces(ctx context.Context, names []string) error
 }
 
 // LoggerPort handles persistence of logs
 type LoggerPort interface {
-	Log(event domain.Event) error
+	// Log appends an event and returns where in the history it was stored
+	Log(ctx context.Context, event domain.Event) (domain.HistoryCursor, error)
 }
 
 // ConfigPort handles loading and saving configuration
 type ConfigPort interface {
-	Load() (*domain.Config, error)
-	Save(config *domain.Config) error
+	Load(ctx context.Context) (*domain.Config, error)
+	Save(ctx context.Context, config *domain.Config) error
 }
 
 // EventRepository (Optional/In-Memory) handles temporary storage for the graph
 // We might just keep this in the service or model, but a port is cleaner for DDD
 type EventRepository interface {
-	Add(event domain.Event)
-	GetRecent(duration time.Duration) []domain.Event
+	Add(ctx context.Context, event domain.Event)
+	GetRecent(ctx context.Context, duration time.Duration) []domain.Event
+}
+
+// HistoryPort gives day-by-day access to previously logged events
+type HistoryPort interface {
+	// Days lists the days that have logged events, oldest first
+	Days() ([]time.Time, error)
+	ReadEvents(date time.Time) ([]domain.Event, error)
+}
+
+// SnoozeStore persists snoozes so they survive restarts and can be shared
+// between awdl-mon processes
+type SnoozeStore interface {
+	Load() ([]domain.Snooze, error)
+	Save(snoozes []domain.Snooze) error
+}
+
+// ProcessPort lists the processes running on the machine
+type ProcessPort interface {
+	// RunningProcesses returns the executable names of every process
+	RunningProcesses() ([]string, error)
 }
 
 // SystemPort handles system-level checks and operations
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// upStreak tracks how long an interface has been seen UP
+type upStreak struct {
+	since    time.Time
+	readings int
+	// downs counts the consecutive non-UP readings since the last UP one
+	downs int
+}
+
+// debouncer decides when an UP interface has been UP long enough to act on
+type debouncer struct {
+	streaks map[string]*upStreak
+}
+
+func newDebouncer() *debouncer {
+	return &debouncer{streaks: make(map[string]*upStreak)}
+}
+
+// observe records a reading and reports whether the interface should be
+// disabled now. Both the reading count and the minimum time must be reached
+// when both are configured. With hysteresis, a streak survives that many
+// non-UP readings before it is forgotten.
+func (d *debouncer) observe(c *domain.Config, name string, status domain.Status, now time.Time) (*upStreak, bool) {
+	streak := d.streaks[name]
+
+	if status != domain.StatusUp {
+		if streak != nil {
+			streak.downs++
+			if streak.downs > c.HysteresisReadings {
+				delete(d.streaks, name)
+			}
+		}
+		return nil, false
+	}
+
+	if streak == nil {
+		streak = &upStreak{since: now}
+		d.streaks[name] = streak
+	}
+	streak.readings++
+	streak.downs = 0
+
+	if streak.readings < c.DebounceReadings || now.Sub(streak.since) < c.DebounceDuration {
+		return streak, false
+	}
+
+	delete(d.streaks, name)
+	return streak, true
+}
+
+// reset forgets the streak of an interface that is not being enforced
+func (d *debouncer) reset(name string) {
+	delete(d.streaks, name)
+}
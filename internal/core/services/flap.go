@@ -0,0 +1,103 @@
+package services
+
+import (
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+)
+
+// flapState is kept for an interface while it is flapping
+type flapState struct {
+	since time.Time
+	// backoff is the current wait between disables, next when the next
+	// disable may happen
+	backoff time.Duration
+	next    time.Time
+}
+
+// flapDetector counts how often each interface had to be disabled and
+// notices when it keeps coming back
+type flapDetector struct {
+	disables map[string][]time.Time
+	flapping map[string]*flapState
+}
+
+func newFlapDetector() *flapDetector {
+	return &flapDetector{
+		disables: make(map[string][]time.Time),
+		flapping: make(map[string]*flapState),
+	}
+}
+
+// hold reports whether the strategy keeps the interface from being enforced
+// right now
+func (f *flapDetector) hold(c *domain.Config, name string, now time.Time) bool {
+	state := f.flapping[name]
+	if state == nil {
+		return false
+	}
+
+	switch c.FlapStrategy {
+	case domain.FlapStop:
+		return true
+	case domain.FlapBackoff:
+		return now.Before(state.next)
+	}
+	return false
+}
+
+// update records whether the interface was just disabled and reports
+// whether it started or stopped flapping. Flapping starts with more than
+// FlapThreshold disables within FlapWindow and stops once at most half as
+// many remain.
+func (f *flapDetector) update(c *domain.Config, name string, disabled bool, now time.Time) (detected, cleared bool, count int) {
+	if c.FlapThreshold <= 0 {
+		cleared = f.flapping[name] != nil
+		delete(f.flapping, name)
+		delete(f.disables, name)
+		return false, cleared, 0
+	}
+
+	times := f.disables[name]
+	if disabled {
+		times = append(times, now)
+	}
+	cutoff := now.Add(-c.FlapWindow)
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	f.disables[name] = times
+	count = len(times)
+
+	state := f.flapping[name]
+	switch {
+	case state == nil && count > c.FlapThreshold:
+		f.flapping[name] = &flapState{since: now, backoff: 2 * c.PollingInterval, next: now.Add(2 * c.PollingInterval)}
+		return true, false, count
+
+	case state != nil && count <= c.FlapThreshold/2:
+		delete(f.flapping, name)
+		return false, true, count
+
+	case state != nil && disabled:
+		state.backoff = min(2*state.backoff, c.FlapWindow)
+		state.next = now.Add(state.backoff)
+	}
+
+	return false, false, count
+}
+
+// reset forgets an interface that is no longer guarded
+func (f *flapDetector) reset(name string) {
+	delete(f.disables, name)
+	delete(f.flapping, name)
+}
+
+// names lists the flapping interfaces
+func (f *flapDetector) names() []string {
+	var names []string
+	for name := range f.flapping {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// HistoryService pages through logged events one chunk at a time so callers
+// never need to hold the whole history in memory
+type HistoryService struct {
+	source ports.HistoryPort
+}
+
+func NewHistoryService(s ports.HistoryPort) *HistoryService {
+	return &HistoryService{source: s}
+}
+
+// Latest returns up to limit of the most recent events, oldest first
+func (s *HistoryService) Latest(limit int) ([]domain.HistoryEntry, error) {
+	days, err := s.days()
+	if err != nil || len(days) == 0 {
+		return nil, err
+	}
+
+	return s.collectBackward(days, len(days)-1, -1, limit)
+}
+
+// Before returns up to limit events logged before the cursor, oldest first
+func (s *HistoryService) Before(c domain.HistoryCursor, limit int) ([]domain.HistoryEntry, error) {
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+
+	key := dayKey(c.Day)
+	for i := len(days) - 1; i >= 0; i-- {
+		switch k := dayKey(days[i]); {
+		case k == key:
+			return s.collectBackward(days, i, c.Index, limit)
+		case k < key:
+			return s.collectBackward(days, i, -1, limit)
+		}
+	}
+
+	return nil, nil
+}
+
+// After returns up to limit events logged after the cursor, oldest first
+func (s *HistoryService) After(c domain.HistoryCursor, limit int) ([]domain.HistoryEntry, error) {
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+
+	key := dayKey(c.Day)
+	for i, day := range days {
+		switch k := dayKey(day); {
+		case k == key:
+			return s.collectForward(days, i, c.Index+1, limit)
+		case k > key:
+			return s.collectForward(days, i, 0, limit)
+		}
+	}
+
+	return nil, nil
+}
+
+// collectBackward walks days from dayIdx towards the oldest. end limits how
+// many events of the first day are considered, -1 meaning all of them.
+func (s *HistoryService) collectBackward(days []time.Time, dayIdx, end, limit int) ([]domain.HistoryEntry, error) {
+	var chunks [][]domain.HistoryEntry
+	remaining := limit
+
+	for i := dayIdx; i >= 0 && remaining > 0; i-- {
+		events, err := s.source.ReadEvents(days[i])
+		if err != nil {
+			return nil, err
+		}
+
+		stop := len(events)
+		if i == dayIdx && end >= 0 && end < stop {
+			stop = end
+		}
+
+		start := stop - remaining
+		if start < 0 {
+			start = 0
+		}
+
+		chunks = append(chunks, toEntries(days[i], events, start, stop))
+		remaining -= stop - start
+	}
+
+	var entries []domain.HistoryEntry
+	for i := len(chunks) - 1; i >= 0; i-- {
+		entries = append(entries, chunks[i]...)
+	}
+	return entries, nil
+}
+
+// collectForward walks days from dayIdx towards the newest, starting at the
+// given event index of the first day
+func (s *HistoryService) collectForward(days []time.Time, dayIdx, start, limit int) ([]domain.HistoryEntry, error) {
+	var entries []domain.HistoryEntry
+
+	for i := dayIdx; i < len(days) && len(entries) < limit; i++ {
+		events, err := s.source.ReadEvents(days[i])
+		if err != nil {
+			return nil, err
+		}
+
+		from := 0
+		if i == dayIdx {
+			from = start
+		}
+		if from > len(events) {
+			from = len(events)
+		}
+
+		stop := from + limit - len(entries)
+		if stop > len(events) {
+			stop = len(events)
+		}
+
+		entries = append(entries, toEntries(days[i], events, from, stop)...)
+	}
+
+	return entries, nil
+}
+
+func (s *HistoryService) days() ([]time.Time, error) {
+	days, err := s.source.Days()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(days, func(i, j int) bool { return dayKey(days[i]) < dayKey(days[j]) })
+
+	return days, nil
+}
+
+func toEntries(day time.Time, events []domain.Event, start, stop int) []domain.HistoryEntry {
+	entries := make([]domain.HistoryEntry, 0, stop-start)
+	for i := start; i < stop; i++ {
+		entries = append(entries, domain.HistoryEntry{
+			Cursor: domain.HistoryCursor{Day: day, Index: i},
+			Event:  events[i],
+		})
+	}
+	return entries
+}
+
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,122 @@
+package services_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
+)
+
+func newHistoryFixture() (*MockHistoryPort, []time.Time) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 3)
+	days := []time.Time{day1, day2, day3}
+
+	logs := map[string][]domain.Event{}
+	for i, day := range days {
+		var events []domain.Event
+		for j := 0; j < 3; j++ {
+			events = append(events, domain.Event{
+				Timestamp: day.Add(time.Duration(j) * time.Hour),
+				Message:   fmt.Sprintf("d%d-e%d", i, j),
+			})
+		}
+		logs[day.Format("2006-01-02")] = events
+	}
+
+	return &MockHistoryPort{
+		DaysFunc: func() ([]time.Time, error) { return days, nil },
+		ReadEventsFunc: func(date time.Time) ([]domain.Event, error) {
+			return logs[date.Format("2006-01-02")], nil
+		},
+	}, days
+}
+
+func messages(entries []domain.HistoryEntry) string {
+	var s string
+	for _, e := range entries {
+		s += e.Event.Message + " "
+	}
+	return s
+}
+
+func TestHistoryService_Latest(t *testing.T) {
+	port, days := newHistoryFixture()
+	service := services.NewHistoryService(port)
+
+	entries, err := service.Latest(4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if got, want := messages(entries), "d1-e2 d2-e0 d2-e1 d2-e2 "; got != want {
+		t.Errorf("Latest() = %q, want %q", got, want)
+	}
+
+	first := entries[0].Cursor
+	if !first.Day.Equal(days[1]) || first.Index != 2 {
+		t.Errorf("Expected cursor of first entry to be day 2 index 2, got %v", first)
+	}
+}
+
+func TestHistoryService_Before(t *testing.T) {
+	port, days := newHistoryFixture()
+	service := services.NewHistoryService(port)
+
+	tests := []struct {
+		name   string
+		cursor domain.HistoryCursor
+		limit  int
+		want   string
+	}{
+		{"Within Day", domain.HistoryCursor{Day: days[2], Index: 2}, 2, "d2-e0 d2-e1 "},
+		{"Across Days", domain.HistoryCursor{Day: days[2], Index: 1}, 3, "d1-e1 d1-e2 d2-e0 "},
+		{"Missing Day", domain.HistoryCursor{Day: days[2].AddDate(0, 0, -1), Index: 0}, 2, "d1-e1 d1-e2 "},
+		{"Start Of History", domain.HistoryCursor{Day: days[0], Index: 1}, 5, "d0-e0 "},
+		{"Nothing Older", domain.HistoryCursor{Day: days[0], Index: 0}, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := service.Before(tt.cursor, tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := messages(entries); got != tt.want {
+				t.Errorf("Before() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryService_After(t *testing.T) {
+	port, days := newHistoryFixture()
+	service := services.NewHistoryService(port)
+
+	tests := []struct {
+		name   string
+		cursor domain.HistoryCursor
+		limit  int
+		want   string
+	}{
+		{"Within Day", domain.HistoryCursor{Day: days[0], Index: 0}, 2, "d0-e1 d0-e2 "},
+		{"Across Days", domain.HistoryCursor{Day: days[0], Index: 2}, 4, "d1-e0 d1-e1 d1-e2 d2-e0 "},
+		{"Missing Day", domain.HistoryCursor{Day: days[1].AddDate(0, 0, 1), Index: 5}, 1, "d2-e0 "},
+		{"End Of History", domain.HistoryCursor{Day: days[2], Index: 2}, 5, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := service.After(tt.cursor, tt.limit)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := messages(entries); got != tt.want {
+				t.Errorf("After() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
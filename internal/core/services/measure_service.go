@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/ports"
+)
+
+// restoreTimeout bounds enabling the interface after a trial
+const restoreTimeout = 5 * time.Second
+
+// MeasureOptions controls a reactivation-latency experiment
+type MeasureOptions struct {
+	Trials int
+	// PollInterval is how often the interface is checked while waiting
+	PollInterval time.Duration
+	// Timeout gives up on a trial that does not come back in time
+	Timeout time.Duration
+	// Progress, if set, is called after every trial
+	Progress func(trial int, latency time.Duration, ok bool)
+}
+
+// MeasureService times how quickly the system brings an interface back after
+// it has been disabled
+type MeasureService struct {
+	network ports.NetworkPort
+	now     func() time.Time
+	sleep   func(time.Duration)
+}
+
+func NewMeasureService(n ports.NetworkPort) *MeasureService {
+	return &MeasureService{network: n, now: time.Now, sleep: time.Sleep}
+}
+
+// WithClock replaces the time source and sleep, for tests
+func (s *MeasureService) WithClock(now func() time.Time, sleep func(time.Duration)) *MeasureService {
+	s.now = now
+	s.sleep = sleep
+	return s
+}
+
+// Measure runs the trials one after another. Every trial disables the
+// interface and polls until it is UP again; a trial that does not see it come
+// back, because it timed out, failed or was cancelled, enables the interface
+// itself. It stops between two checks once ctx is done.
+func (s *MeasureService) Measure(ctx context.Context, name string, opts MeasureOptions) (domain.Measurement, error) {
+	result := domain.Measurement{Interface: name}
+	if !domain.ValidInterfaceName(name) {
+		return result, fmt.Errorf("%q is not a valid interface name", name)
+	}
+	if opts.Trials <= 0 || opts.PollInterval <= 0 || opts.Timeout <= 0 {
+		return result, fmt.Errorf("trials, poll interval and timeout must be positive")
+	}
+
+	for trial := 1; trial <= opts.Trials; trial++ {
+		latency, ok, err := s.trial(ctx, name, opts)
+		if err != nil {
+			return result, fmt.Errorf("trial %d: %w", trial, err)
+		}
+
+		if ok {
+			result.Samples = append(result.Samples, latency)
+		} else {
+			result.TimedOut++
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(trial, latency, ok)
+		}
+	}
+
+	summarize(&result)
+	return result, nil
+}
+
+func (s *MeasureService) trial(ctx context.Context, name string, opts MeasureOptions) (latency time.Duration, ok bool, err error) {
+	// A failed disable may still have taken the interface down
+	defer func() {
+		if ok {
+			return
+		}
+		if restoreErr := s.restore(ctx, name); restoreErr != nil {
+			err = errors.Join(err, fmt.Errorf("restoring %s: %w", name, restoreErr))
+		}
+	}()
+
+	if err := s.network.DisableInterface(ctx, name); err != nil {
+		return 0, false, err
+	}
+	start := s.now()
+
+	for {
+		s.sleep(opts.PollInterval)
+		if err := ctx.Err(); err != nil {
+			return 0, false, err
+		}
+
+		status, err := s.network.CheckInterface(ctx, name)
+		if err != nil {
+			return 0, false, err
+		}
+
+		elapsed := s.now().Sub(start)
+		if status == domain.StatusUp {
+			return elapsed, true, nil
+		}
+		if elapsed >= opts.Timeout {
+			return elapsed, false, nil
+		}
+	}
+}
+
+// restore enables the interface with a deadline of its own, since ctx may
+// already be done
+func (s *MeasureService) restore(ctx context.Context, name string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+	defer cancel()
+	return s.network.EnableInterface(ctx, name)
+}
+
+// summarize fills in the statistics of the samples, using the nearest-rank
+// method for the 95th percentile
+func summarize(m *domain.Measurement) {
+	n := len(m.Samples)
+	if n == 0 {
+		return
+	}
+
+	sorted := append([]time.Duration{}, m.Samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	m.Min = sorted[0]
+	m.Max = sorted[n-1]
+	if n%2 == 1 {
+		m.Median = sorted[n/2]
+	} else {
+		m.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	m.P95 = sorted[int(math.Ceil(0.95*float64(n)))-1]
+}
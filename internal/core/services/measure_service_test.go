@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
+)
+
+// scriptedNetwork brings the interface back after a scripted number of
+// checks per trial; a negative count never brings it back
+type scriptedNetwork struct {
+	script   []int
+	trial    int
+	checks   int
+	up       bool
+	enables  int
+	disables int
+	// enableErr is the state of the context the last enable got
+	enableErr error
+}
+
+func (s *scriptedNetwork) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	if !s.up {
+		s.checks++
+		if remaining := s.script[s.trial-1]; remaining >= 0 && s.checks >= remaining {
+			s.up = true
+		}
+	}
+	if s.up {
+		return domain.StatusUp, nil
+	}
+	return domain.StatusDown, nil
+}
+
+func (s *scriptedNetwork) DescribeInterface(ctx context.Context, name string) (domain.Interface, error) {
+	status, err := s.CheckInterface(ctx, name)
+	return domain.Interface{Name: name, Status: status}, err
+}
+
+func (s *scriptedNetwork) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	return nil, nil
+}
+
+func (s *scriptedNetwork) DisableInterface(ctx context.Context, name string) error {
+	s.disables++
+	s.trial++
+	s.checks = 0
+	s.up = false
+	return nil
+}
+
+func (s *scriptedNetwork) EnableInterface(ctx context.Context, name string) error {
+	s.enables++
+	s.enableErr = ctx.Err()
+	s.up = true
+	return nil
+}
+
+func TestMeasureService_Measure(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	sleep := func(d time.Duration) { now = now.Add(d) }
+
+	network := &scriptedNetwork{script: []int{3, 1, 10, -1, 5, 2}}
+	service := services.NewMeasureService(network).WithClock(clock, sleep)
+
+	var progress []bool
+	result, err := service.Measure(context.Background(), "awdl0", services.MeasureOptions{
+		Trials:       6,
+		PollInterval: 10 * time.Millisecond,
+		Timeout:      time.Second,
+		Progress: func(trial int, latency time.Duration, ok bool) {
+			progress = append(progress, ok)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	ms := time.Millisecond
+	want := domain.Measurement{
+		Interface: "awdl0",
+		Samples:   []time.Duration{30 * ms, 10 * ms, 100 * ms, 50 * ms, 20 * ms},
+		TimedOut:  1,
+		Min:       10 * ms,
+		Median:    30 * ms,
+		P95:       100 * ms,
+		Max:       100 * ms,
+	}
+
+	if len(result.Samples) != len(want.Samples) {
+		t.Fatalf("Expected samples %v, got %v", want.Samples, result.Samples)
+	}
+	for i := range want.Samples {
+		if result.Samples[i] != want.Samples[i] {
+			t.Errorf("Expected samples %v, got %v", want.Samples, result.Samples)
+			break
+		}
+	}
+	if result.TimedOut != want.TimedOut || result.Min != want.Min || result.Median != want.Median ||
+		result.P95 != want.P95 || result.Max != want.Max {
+		t.Errorf("Expected %+v, got %+v", want, result)
+	}
+	if network.disables != 6 || network.enables != 1 {
+		t.Errorf("Expected 6 disables and the timed out trial to be restored, got %d and %d", network.disables, network.enables)
+	}
+	if len(progress) != 6 || progress[3] {
+		t.Errorf("Expected progress for every trial with the fourth timing out, got %v", progress)
+	}
+}
+
+func TestMeasureService_MeasureEvenMedian(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	network := &scriptedNetwork{script: []int{1, 2, 3, 4}}
+	service := services.NewMeasureService(network).
+		WithClock(func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) })
+
+	result, err := service.Measure(context.Background(), "awdl0", services.MeasureOptions{Trials: 4, PollInterval: time.Millisecond, Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Median != 2500*time.Microsecond || result.P95 != 4*time.Millisecond {
+		t.Errorf("Expected a median of 2.5ms and p95 of 4ms, got %v and %v", result.Median, result.P95)
+	}
+}
+
+func TestMeasureService_MeasureErrors(t *testing.T) {
+	service := services.NewMeasureService(&MockNetworkPort{
+		DisableFunc: func(name string) error { return errors.New("not permitted") },
+	})
+
+	if _, err := service.Measure(context.Background(), "awdl0", services.MeasureOptions{}); err == nil {
+		t.Error("Expected invalid options to be rejected")
+	}
+
+	_, err := service.Measure(context.Background(), "awdl0", services.MeasureOptions{Trials: 1, PollInterval: time.Millisecond, Timeout: time.Second})
+	if err == nil {
+		t.Error("Expected the disable error to be returned")
+	}
+}
+
+func TestMeasureService_MeasureRestoresWhenCancelled(t *testing.T) {
+	network := &scriptedNetwork{script: []int{-1}}
+	ctx, cancel := context.WithCancel(context.Background())
+	service := services.NewMeasureService(network).WithClock(time.Now, func(time.Duration) { cancel() })
+
+	_, err := service.Measure(ctx, "awdl0", services.MeasureOptions{Trials: 1, PollInterval: time.Millisecond, Timeout: time.Minute})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the cancellation to be returned, got %v", err)
+	}
+	if network.enables != 1 || network.enableErr != nil {
+		t.Errorf("Expected the interface to be enabled with a live context, got %d enables, %v", network.enables, network.enableErr)
+	}
+}
+
+func TestMeasureService_MeasureRestoresAfterCheckError(t *testing.T) {
+	var enabled []string
+	service := services.NewMeasureService(&MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) { return domain.StatusUnknown, errors.New("ifconfig crashed") },
+		EnableFunc: func(name string) error {
+			enabled = append(enabled, name)
+			return nil
+		},
+	}).WithClock(time.Now, func(time.Duration) {})
+
+	_, err := service.Measure(context.Background(), "awdl0", services.MeasureOptions{Trials: 1, PollInterval: time.Millisecond, Timeout: time.Minute})
+	if err == nil {
+		t.Fatal("Expected the check error to be returned")
+	}
+	if len(enabled) != 1 {
+		t.Errorf("Expected the interface to be enabled again, got %v", enabled)
+	}
+}
@@ -1,26 +1,43 @@
 package services_test
 
 import (
-	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
+	"context"
 	"time"
+
+	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 )
 
 type MockNetworkPort struct {
-	CheckFunc   func(name string) (domain.Status, error)
-	DisableFunc func(name string) error
-	EnableFunc  func(name string) error
+	CheckFunc    func(name string) (domain.Status, error)
+	DescribeFunc func(name string) (domain.Interface, error)
+	ListFunc     func() ([]domain.Interface, error)
+	DisableFunc  func(name string) error
+	EnableFunc   func(name string) error
 }
 
-func (m *MockNetworkPort) CheckInterface(name string) (domain.Status, error) {
+func (m *MockNetworkPort) CheckInterface(_ context.Context, name string) (domain.Status, error) {
 	return m.CheckFunc(name)
 }
-func (m *MockNetworkPort) DisableInterface(name string) error {
+func (m *MockNetworkPort) DescribeInterface(_ context.Context, name string) (domain.Interface, error) {
+	if m.DescribeFunc != nil {
+		return m.DescribeFunc(name)
+	}
+	status, err := m.CheckFunc(name)
+	return domain.Interface{Name: name, Status: status}, err
+}
+func (m *MockNetworkPort) ListInterfaces(context.Context) ([]domain.Interface, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	return nil, nil
+}
+func (m *MockNetworkPort) DisableInterface(_ context.Context, name string) error {
 	if m.DisableFunc != nil {
 		return m.DisableFunc(name)
 	}
 	return nil
 }
-func (m *MockNetworkPort) EnableInterface(name string) error {
+func (m *MockNetworkPort) EnableInterface(_ context.Context, name string) error {
 	if m.EnableFunc != nil {
 		return m.EnableFunc(name)
 	}
@@ -31,11 +48,11 @@ type MockLoggerPort struct {
 	LogFunc func(event domain.Event) error
 }
 
-func (m *MockLoggerPort) Log(event domain.Event) error {
+func (m *MockLoggerPort) Log(_ context.Context, event domain.Event) (domain.HistoryCursor, error) {
 	if m.LogFunc != nil {
-		return m.LogFunc(event)
+		return domain.HistoryCursor{}, m.LogFunc(event)
 	}
-	return nil
+	return domain.HistoryCursor{}, nil
 }
 
 type MockEventRepo struct {
@@ -43,14 +60,55 @@ type MockEventRepo struct {
 	GetRecentFunc func(duration time.Duration) []domain.Event
 }
 
-func (m *MockEventRepo) Add(event domain.Event) {
+func (m *MockEventRepo) Add(_ context.Context, event domain.Event) {
 	if m.AddFunc != nil {
 		m.AddFunc(event)
 	}
 }
-func (m *MockEventRepo) GetRecent(duration time.Duration) []domain.Event {
+func (m *MockEventRepo) GetRecent(_ context.Context, duration time.Duration) []domain.Event {
 	if m.GetRecentFunc != nil {
 		return m.GetRecentFunc(duration)
 	}
 	return nil
 }
+
+type MockHistoryPort struct {
+	DaysFunc       func() ([]time.Time, error)
+	ReadEventsFunc func(date time.Time) ([]domain.Event, error)
+}
+
+func (m *MockHistoryPort) Days() ([]time.Time, error) {
+	if m.DaysFunc != nil {
+		return m.DaysFunc()
+	}
+	return nil, nil
+}
+func (m *MockHistoryPort) ReadEvents(date time.Time) ([]domain.Event, error) {
+	if m.ReadEventsFunc != nil {
+		return m.ReadEventsFunc(date)
+	}
+	return nil, nil
+}
+
+type MockSnoozeStore struct {
+	Snoozes []domain.Snooze
+	Saves   int
+}
+
+func (m *MockSnoozeStore) Load() ([]domain.Snooze, error) {
+	return append([]domain.Snooze{}, m.Snoozes...), nil
+}
+func (m *MockSnoozeStore) Save(snoozes []domain.Snooze) error {
+	m.Snoozes = append([]domain.Snooze{}, snoozes...)
+	m.Saves++
+	return nil
+}
+
+type MockProcessPort struct {
+	Running []string
+	Err     error
+}
+
+func (m *MockProcessPort) RunningProcesses() ([]string, error) {
+	return m.Running, m.Err
+}
@@ -1,6 +1,12 @@
 package services
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
@@ -8,86 +14,885 @@ import (
 )
 
 type MonitorService struct {
-	network ports.NetworkPort
-	logger  ports.LoggerPort
-	repo    ports.EventRepository
-	config  *domain.Config
+	network   ports.NetworkPort
+	logger    ports.LoggerPort
+	repo      ports.EventRepository
+	config    *domain.Config
+	snoozes   ports.SnoozeStore
+	processes ports.ProcessPort
+	allowList ports.AllowListPort
+	now       func() time.Time
+	// observeOnly records what would be done instead of changing anything
+	observeOnly bool
+
+	// work serializes ticks with everything else that changes interfaces or
+	// snoozes, so a tick never acts on what it read before such a change and
+	// nothing is disabled again after the interfaces have been restored. The
+	// config only changes while both work and mu are held.
+	work sync.Mutex
+
+	// mu guards the state below, which the UI reads while a tick runs
+	mu       sync.Mutex
+	allowed  map[string]time.Time
+	debounce *debouncer
+	flaps    *flapDetector
+	// states is the last reading per interface
+	states map[string]reading
+	// wouldDisable holds, per interface, the start of the UP state a
+	// WouldDisable was last recorded for
+	wouldDisable map[string]time.Time
+	// interval is the adaptive polling interval
+	interval time.Duration
+	// mode is the effective mode, set by scheduleMode or overridden by rule
+	mode         domain.Mode
+	scheduleMode domain.Mode
+	rule         *domain.ProcessRule
 }
 
 func NewMonitorService(n ports.NetworkPort, l ports.LoggerPort, r ports.EventRepository, c *domain.Config) *MonitorService {
 	return &MonitorService{
-		network: n,
-		logger:  l,
-		repo:    r,
-		config:  c,
+		network:  n,
+		logger:   l,
+		repo:     r,
+		config:   c,
+		now:      time.Now,
+		allowed:  make(map[string]time.Time),
+		debounce: newDebouncer(),
+		flaps:    newFlapDetector(),
+		states:   make(map[string]reading),
+
+		wouldDisable: make(map[string]time.Time),
+	}
+}
+
+// WithSnoozeStore persists snoozes, so they are shared with other processes
+// and survive restarts
+func (s *MonitorService) WithSnoozeStore(store ports.SnoozeStore) *MonitorService {
+	s.snoozes = store
+	return s
+}
+
+// WithAllowList keeps the allow list of a privileged helper in step with
+// the guarded interfaces
+func (s *MonitorService) WithAllowList(allowList ports.AllowListPort) *MonitorService {
+	s.allowList = allowList
+	return s
+}
+
+// WithProcesses enables process rules, which need the running processes
+func (s *MonitorService) WithProcesses(processes ports.ProcessPort) *MonitorService {
+	s.processes = processes
+	return s
+}
+
+// WithObserveOnly never disables or enables an interface. Interfaces are
+// checked as usual, and a WouldDisable event is recorded once per UP state
+// instead of disabling it.
+func (s *MonitorService) WithObserveOnly() *MonitorService {
+	s.observeOnly = true
+	return s
+}
+
+// ObserveOnly reports whether the monitor runs without changing interfaces
+func (s *MonitorService) ObserveOnly() bool {
+	return s.observeOnly
+}
+
+// WithClock replaces the time source, for tests
+func (s *MonitorService) WithClock(now func() time.Time) *MonitorService {
+	s.now = now
+	return s
+}
+
+// Tick checks every guarded interface and disables the ones that are UP,
+// except while they are snoozed or the policy says to observe. It stops as
+// soon as ctx is done.
+func (s *MonitorService) Tick(ctx context.Context) ([]domain.Event, error) {
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	now := s.now()
+	events, err := s.expireSnoozes(ctx, now)
+
+	mode, policyEvents, policyErr := s.followPolicy(ctx, now)
+	events = append(events, policyEvents...)
+	err = errors.Join(err, policyErr)
+
+	for _, name := range s.config.Interfaces {
+		if ctx.Err() != nil {
+			return events, errors.Join(err, ctx.Err())
+		}
+
+		// Interfaces are checked even when they are not enforced, so state
+		// changes we did not cause are recorded too
+		status, checkErr := s.check(ctx, name)
+		if checkErr != nil {
+			// A cancelled tick says nothing about the interface
+			if ctx.Err() != nil {
+				return events, errors.Join(err, checkErr)
+			}
+			events = append(events, s.checkFailed(ctx, name, checkErr)...)
+			// A missing interface is a state, not a failure
+			if !errors.Is(checkErr, domain.ErrInterfaceNotFound) {
+				err = errors.Join(err, checkErr)
+			}
+			continue
+		}
+		events = append(events, s.transition(ctx, name, status)...)
+
+		if mode == domain.ModeObserve || s.isAllowed(name, now) {
+			s.mu.Lock()
+			s.debounce.reset(name)
+			s.flaps.reset(name)
+			s.mu.Unlock()
+			continue
+		}
+
+		s.mu.Lock()
+		held := s.flaps.hold(s.config, name, now)
+		s.mu.Unlock()
+
+		var enforced []domain.Event
+		if !held {
+			var enforceErr error
+			enforced, enforceErr = s.enforce(ctx, name, status, now)
+			err = errors.Join(err, enforceErr)
+			events = append(events, enforced...)
+		}
+
+		events = append(events, s.watchFlapping(ctx, name, hasDisable(enforced), now)...)
 	}
+
+	s.adaptInterval(events)
+
+	return events, err
 }
 
-func (s *MonitorService) Tick() (*domain.Event, error) {
-	status, err := s.network.CheckInterface("awdl0")
+// enforce disables an UP interface once it has been UP long enough, and
+// verifies that it went down
+func (s *MonitorService) enforce(ctx context.Context, name string, status domain.Status, now time.Time) ([]domain.Event, error) {
+	s.mu.Lock()
+	streak, act := s.debounce.observe(s.config, name, status, now)
+	s.mu.Unlock()
+
+	if !act {
+		return nil, nil
+	}
+
+	message := fmt.Sprintf("%s detected UP.", name)
+	if streak.readings > 1 {
+		message = fmt.Sprintf("%s UP for %d readings.", name, streak.readings)
+	}
+
+	if s.observeOnly {
+		return s.pretendDisable(ctx, name, message, streak), nil
+	}
+
+	if err := s.disable(ctx, name); err != nil {
+		return nil, err
+	}
+	message += " Disabling..."
+
+	// The interface counts as up from the first UP reading until a check
+	// confirms it is down again
+	verified, err := s.check(ctx, name)
 	if err != nil {
 		return nil, err
 	}
+	if verified == domain.StatusUp {
+		message += " still UP afterwards"
+	}
 
-	if status != domain.StatusUp {
-		return nil, nil
+	evt := s.recordEvent(ctx, domain.Event{
+		Type:       domain.EventDisable,
+		Interface:  name,
+		Message:    message,
+		UpDuration: s.now().Sub(streak.since),
+	})
+
+	return append([]domain.Event{evt}, s.transition(ctx, name, verified)...), nil
+}
+
+// pretendDisable records a WouldDisable event, once per UP state
+func (s *MonitorService) pretendDisable(ctx context.Context, name, message string, streak *upStreak) []domain.Event {
+	s.mu.Lock()
+	since := s.states[name].since
+	repeated := s.wouldDisable[name].Equal(since)
+	s.wouldDisable[name] = since
+	s.mu.Unlock()
+
+	if repeated {
+		return nil
 	}
 
-	if err := s.network.DisableInterface("awdl0"); err != nil {
+	return []domain.Event{s.recordEvent(ctx, domain.Event{
+		Type:       domain.EventWouldDisable,
+		Interface:  name,
+		Message:    message + " Would disable",
+		UpDuration: s.now().Sub(streak.since),
+	})}
+}
+
+// transition records a Check event when an interface is seen in a different
+// state than last time, whoever changed it
+func (s *MonitorService) transition(ctx context.Context, name string, status domain.Status) []domain.Event {
+	return s.observe(ctx, name, reading{status: status})
+}
+
+// checkFailed records an interface that could not be checked as Absent or
+// Unknown, with the reason, and forgets its UP streak
+func (s *MonitorService) checkFailed(ctx context.Context, name string, err error) []domain.Event {
+	s.mu.Lock()
+	s.debounce.reset(name)
+	s.mu.Unlock()
+
+	switch {
+	case errors.Is(err, domain.ErrInterfaceNotFound):
+		return s.observe(ctx, name, reading{status: domain.StatusAbsent})
+	case errors.Is(err, domain.ErrPermissionDenied):
+		return s.observe(ctx, name, reading{status: domain.StatusUnknown, reason: domain.ErrPermissionDenied.Error()})
+	default:
+		return s.observe(ctx, name, reading{status: domain.StatusUnknown, reason: err.Error()})
+	}
+}
+
+// reading is what a check found out about an interface
+type reading struct {
+	status domain.Status
+	// reason explains an Unknown status
+	reason string
+	// since is when the interface was first seen in this state
+	since time.Time
+}
+
+// observe records a Check event when the reading differs from the last one
+func (s *MonitorService) observe(ctx context.Context, name string, r reading) []domain.Event {
+	s.mu.Lock()
+	previous, known := s.states[name]
+	if known && previous.status == r.status && previous.reason == r.reason {
+		s.mu.Unlock()
+		return nil
+	}
+	r.since = s.now()
+	s.states[name] = r
+	s.mu.Unlock()
+
+	msg := fmt.Sprintf("%s is %s", name, r.status)
+	if r.reason != "" {
+		msg += ": " + r.reason
+	}
+
+	return []domain.Event{s.recordEvent(ctx, domain.Event{
+		Type:      domain.EventCheck,
+		Interface: name,
+		Message:   msg,
+		Status:    r.status,
+	})}
+}
+
+// Describe returns the live details of an interface
+func (s *MonitorService) Describe(ctx context.Context, name string) (domain.Interface, error) {
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	return s.network.DescribeInterface(ctx, name)
+}
+
+// StateSince returns when the interface entered the state it was last seen
+// in, zero if it has not been checked yet
+func (s *MonitorService) StateSince(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.states[name].since
+}
+
+// ListInterfaces describes every interface of the system
+func (s *MonitorService) ListInterfaces(ctx context.Context) ([]domain.Interface, error) {
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	return s.network.ListInterfaces(ctx)
+}
+
+// SetInterfaces replaces the guarded interfaces once a running tick has
+// finished. Interfaces that are no longer guarded are enabled again, as
+// quitting does.
+func (s *MonitorService) SetInterfaces(ctx context.Context, names []string) ([]domain.Event, error) {
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	candidate := s.config.Clone()
+	candidate.Interfaces = append([]string{}, names...)
+
+	var verrs domain.ValidationErrors
+	if errors.As(candidate.Validate(), &verrs) {
+		if msg, ok := verrs.Field("interfaces"); ok {
+			return nil, fmt.Errorf("interfaces: %s", msg)
+		}
+	}
+
+	return s.replaceConfig(ctx, candidate)
+}
+
+// UpdateConfig replaces the configuration once a running tick has finished.
+// Interfaces that are no longer guarded are enabled again, as SetInterfaces
+// does.
+func (s *MonitorService) UpdateConfig(ctx context.Context, c *domain.Config) ([]domain.Event, error) {
+	if err := c.Validate(); err != nil {
 		return nil, err
 	}
 
-	evt := domain.Event{
-		Timestamp: time.Now(),
-		Type:      domain.EventDisable,
-		Message:   "awdl0 detected UP. Disabling...",
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	return s.replaceConfig(ctx, c.Clone())
+}
+
+// replaceConfig swaps in next and releases the interfaces it no longer
+// guards. The caller holds work. The dropped interfaces stay on the allow
+// list until they have been enabled again.
+func (s *MonitorService) replaceConfig(ctx context.Context, next *domain.Config) ([]domain.Event, error) {
+	dropped := s.dropped(next.Interfaces)
+	if err := s.allow(ctx, append(append([]string{}, next.Interfaces...), dropped...)); err != nil {
+		return nil, err
 	}
 
-	_ = s.logger.Log(evt)
+	s.mu.Lock()
+	*s.config = *next
+	s.mu.Unlock()
 
-	s.repo.Add(evt)
+	events, err := s.release(ctx, dropped)
+	if len(dropped) > 0 {
+		err = errors.Join(err, s.allow(ctx, next.Interfaces))
+	}
+	return events, err
+}
 
-	return &evt, nil
+// allow replaces the allow list, if there is one
+func (s *MonitorService) allow(ctx context.Context, names []string) error {
+	if s.allowList == nil {
+		return nil
+	}
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	if err := s.allowList.AllowInterfaces(ctx, names); err != nil {
+		return fmt.Errorf("allowing interfaces: %w", err)
+	}
+	return nil
+}
+
+// dropped lists the guarded interfaces missing from names
+func (s *MonitorService) dropped(names []string) []string {
+	guarded := make(map[string]bool)
+	for _, name := range names {
+		guarded[name] = true
+	}
+
+	var dropped []string
+	for _, name := range s.config.Interfaces {
+		if !guarded[name] {
+			dropped = append(dropped, name)
+		}
+	}
+	return dropped
+}
+
+// release forgets the state of interfaces that are no longer guarded and
+// enables them again
+func (s *MonitorService) release(ctx context.Context, names []string) ([]domain.Event, error) {
+	var events []domain.Event
+	var err error
+	for _, name := range names {
+		s.mu.Lock()
+		s.debounce.reset(name)
+		s.flaps.reset(name)
+		s.mu.Unlock()
+
+		if s.observeOnly {
+			continue
+		}
+		if enableErr := s.enable(ctx, name); enableErr != nil {
+			err = errors.Join(err, enableErr)
+			continue
+		}
+		events = append(events, s.record(ctx, domain.EventEnable, name, fmt.Sprintf("%s enabled, no longer guarded", name)))
+	}
+
+	return events, err
 }
 
-func (s *MonitorService) ToggleInterface() (*domain.Event, error) {
-	status, err := s.network.CheckInterface("awdl0")
+// ToggleInterface brings an interface down when it is UP and up otherwise,
+// once a running tick has finished
+func (s *MonitorService) ToggleInterface(ctx context.Context, name string) (*domain.Event, error) {
+	if s.observeOnly {
+		return nil, domain.ErrObserveOnly
+	}
+
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	status, err := s.check(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 
 	var evt domain.Event
 	if status == domain.StatusUp {
-		if err := s.network.DisableInterface("awdl0"); err != nil {
+		if err := s.disable(ctx, name); err != nil {
 			return nil, err
 		}
-		evt = domain.Event{
-			Timestamp: time.Now(),
-			Type:      domain.EventDisable,
-			Message:   "awdl0 manually disabled",
-		}
+		evt = s.record(ctx, domain.EventDisable, name, fmt.Sprintf("%s manually disabled", name))
 	} else {
-		if err := s.network.EnableInterface("awdl0"); err != nil {
+		if err := s.enable(ctx, name); err != nil {
 			return nil, err
 		}
-		evt = domain.Event{
-			Timestamp: time.Now(),
-			Type:      domain.EventEnable,
-			Message:   "awdl0 manually enabled",
+		evt = s.record(ctx, domain.EventEnable, name, fmt.Sprintf("%s manually enabled", name))
+	}
+
+	return &evt, nil
+}
+
+// Allow enables an interface and stops guarding it for the given duration
+func (s *MonitorService) Allow(ctx context.Context, name string, d time.Duration) (*domain.Event, error) {
+	if !domain.ValidInterfaceName(name) {
+		return nil, fmt.Errorf("%q is not a valid interface name", name)
+	}
+	if d <= 0 || d > domain.MaxSnooze {
+		return nil, fmt.Errorf("snooze must be greater than 0s and at most %v", domain.MaxSnooze)
+	}
+
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	until := s.now().Add(d)
+
+	if err := s.updateSnoozes(func(allowed map[string]time.Time) {
+		allowed[name] = until
+	}); err != nil {
+		return nil, err
+	}
+
+	// In observe-only mode the interface was never disabled
+	if !s.observeOnly {
+		if err := s.enable(ctx, name); err != nil {
+			return nil, err
 		}
 	}
 
-	_ = s.logger.Log(evt)
+	evt := s.record(ctx, domain.EventSnoozeStart, name,
+		fmt.Sprintf("%s allowed for %v (until %s)", name, d, until.Format("15:04:05")))
+
+	return &evt, nil
+}
+
+// EndSnooze resumes guarding an interface before its snooze runs out
+func (s *MonitorService) EndSnooze(ctx context.Context, name string) (*domain.Event, error) {
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	found := false
+	if err := s.updateSnoozes(func(allowed map[string]time.Time) {
+		_, found = allowed[name]
+		delete(allowed, name)
+	}); err != nil {
+		return nil, err
+	}
+
+	if !found {
+		return nil, nil
+	}
 
-	s.repo.Add(evt)
+	evt := s.record(ctx, domain.EventSnoozeEnd, name, fmt.Sprintf("%s snooze cancelled, guarding again", name))
 
 	return &evt, nil
 }
 
+// Schedule returns the schedule state right now, and false when no schedule
+// is configured
+func (s *MonitorService) Schedule() (domain.ScheduleState, bool) {
+	s.mu.Lock()
+	schedule := s.config.Schedule
+	s.mu.Unlock()
+
+	if schedule == nil {
+		return domain.ScheduleState{}, false
+	}
+	return schedule.At(s.now()), true
+}
+
+// watchFlapping logs when an interface starts or stops flapping
+func (s *MonitorService) watchFlapping(ctx context.Context, name string, disabled bool, now time.Time) []domain.Event {
+	s.mu.Lock()
+	detected, cleared, count := s.flaps.update(s.config, name, disabled, now)
+	s.mu.Unlock()
+
+	switch {
+	case detected:
+		return []domain.Event{s.record(ctx, domain.EventFlapDetected, name,
+			fmt.Sprintf("%s came back %d times in %v, flapping (%s)", name, count, s.config.FlapWindow, s.config.FlapStrategy))}
+	case cleared:
+		return []domain.Event{s.record(ctx, domain.EventFlapCleared, name, fmt.Sprintf("%s stopped flapping", name))}
+	}
+	return nil
+}
+
+// Flapping lists the interfaces that are currently flapping
+func (s *MonitorService) Flapping() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := s.flaps.names()
+	sort.Strings(names)
+	return names
+}
+
+// PollInterval is how long to wait before the next tick. The aggressive
+// flap strategy polls as fast as allowed while an interface flaps.
+func (s *MonitorService) PollInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.config.FlapStrategy == domain.FlapAggressive && len(s.flaps.names()) > 0 {
+		return domain.MinPollingInterval
+	}
+
+	if s.config.AdaptivePolling && s.interval > 0 {
+		return s.interval
+	}
+
+	return s.config.PollingInterval
+}
+
+// NextPoll is PollInterval plus a random jitter of up to PollJitter
+func (s *MonitorService) NextPoll() time.Duration {
+	interval := s.PollInterval()
+
+	s.mu.Lock()
+	jitter := s.config.PollJitter
+	s.mu.Unlock()
+
+	if jitter > 0 {
+		interval += rand.N(jitter + 1)
+	}
+	return interval
+}
+
+// adaptInterval drops the adaptive interval to the minimum after a
+// reactivation, disabled or only recorded in observe-only mode, and doubles it towards the ceiling after a quiet tick
+func (s *MonitorService) adaptInterval(events []domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.config.AdaptivePolling {
+		s.interval = 0
+		return
+	}
+
+	ceiling := min(max(s.config.AdaptiveCeiling, domain.MinPollingInterval), domain.MaxPollingInterval)
+
+	switch {
+	case hasReactivation(events):
+		s.interval = domain.MinPollingInterval
+	case s.interval == 0:
+		s.interval = min(s.config.PollingInterval, ceiling)
+	default:
+		s.interval = min(2*s.interval, ceiling)
+	}
+}
+
+// hasReactivation reports whether an interface was found UP and disabled, or
+// would have been
+func hasReactivation(events []domain.Event) bool {
+	for _, e := range events {
+		if e.Type == domain.EventDisable || e.Type == domain.EventWouldDisable {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDisable(events []domain.Event) bool {
+	for _, e := range events {
+		if e.Type == domain.EventDisable {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveRule returns the process rule in effect since the last tick, if any
+func (s *MonitorService) ActiveRule() (domain.ProcessRule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rule == nil {
+		return domain.ProcessRule{}, false
+	}
+	return *s.rule, true
+}
+
+// followPolicy returns the mode in effect and logs every schedule or process
+// rule transition since the last tick. Entering observe mode enables the
+// guarded interfaces again, as quitting does.
+func (s *MonitorService) followPolicy(ctx context.Context, now time.Time) (domain.Mode, []domain.Event, error) {
+	var events []domain.Event
+
+	state := domain.ScheduleState{Mode: domain.ModeEnforce}
+	if s.config.Schedule != nil {
+		state = s.config.Schedule.At(now)
+	}
+
+	rule, process, err := s.matchProcessRule()
+
+	s.mu.Lock()
+	previousSchedule, previousRule, previousMode := s.scheduleMode, s.rule, s.mode
+	s.scheduleMode, s.rule = state.Mode, rule
+	mode := state.Mode
+	if rule != nil {
+		mode = rule.Mode
+	}
+	s.mode = mode
+	s.mu.Unlock()
+
+	// Without a schedule the monitor always enforced, so there is nothing
+	// to report on the first tick
+	if previousSchedule != state.Mode && (previousSchedule != "" || s.config.Schedule != nil) {
+		reason := "default"
+		if state.Rule != "" {
+			reason = state.Rule
+		}
+		message := fmt.Sprintf("Schedule: %s (%s)", state.Mode, reason)
+		if !state.Next.IsZero() {
+			message += fmt.Sprintf(", %s from %s", state.NextMode, state.Next.Format("Mon 15:04"))
+		}
+		events = append(events, s.record(ctx, domain.EventSchedule, "", message))
+	}
+
+	if ruleName(previousRule) != ruleName(rule) {
+		if previousRule != nil {
+			events = append(events, s.record(ctx, domain.EventRule, "", fmt.Sprintf("Rule %q no longer active", previousRule.Name)))
+		}
+		if rule != nil {
+			events = append(events, s.record(ctx, domain.EventRule, "",
+				fmt.Sprintf("Rule %q active (%s running): %s", rule.Name, process, rule.Mode)))
+		}
+	}
+
+	if mode == domain.ModeObserve && previousMode == domain.ModeEnforce && !s.observeOnly {
+		for _, name := range s.config.Interfaces {
+			if enableErr := s.enable(ctx, name); enableErr != nil {
+				err = errors.Join(err, enableErr)
+				continue
+			}
+			events = append(events, s.record(ctx, domain.EventEnable, name, fmt.Sprintf("%s enabled while observing", name)))
+		}
+	}
+
+	return mode, events, err
+}
+
+// matchProcessRule returns the first process rule with a running process.
+// When processes cannot be listed, the last matched rule stays in effect.
+func (s *MonitorService) matchProcessRule() (*domain.ProcessRule, string, error) {
+	if s.processes == nil || len(s.config.ProcessRules) == 0 {
+		return nil, "", nil
+	}
+
+	running, err := s.processes.RunningProcesses()
+	if err != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.rule, "", fmt.Errorf("listing processes: %w", err)
+	}
+
+	for _, rule := range s.config.ProcessRules {
+		if process, ok := rule.Match(running); ok {
+			return &rule, process, nil
+		}
+	}
+	return nil, "", nil
+}
+
+func ruleName(rule *domain.ProcessRule) string {
+	if rule == nil {
+		return ""
+	}
+	return rule.Name
+}
+
+// Snoozes returns the active snoozes, soonest to end first
+func (s *MonitorService) Snoozes() []domain.Snooze {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	var snoozes []domain.Snooze
+	for name, until := range s.allowed {
+		if until.After(now) {
+			snoozes = append(snoozes, domain.Snooze{Interface: name, Until: until})
+		}
+	}
+
+	sort.Slice(snoozes, func(i, j int) bool { return snoozes[i].Until.Before(snoozes[j].Until) })
+
+	return snoozes
+}
+
+// expireSnoozes picks up snoozes set by other processes and ends the ones
+// that ran out
+func (s *MonitorService) expireSnoozes(ctx context.Context, now time.Time) ([]domain.Event, error) {
+	var expired []string
+
+	err := s.updateSnoozes(func(allowed map[string]time.Time) {
+		for name, until := range allowed {
+			if !until.After(now) {
+				expired = append(expired, name)
+				delete(allowed, name)
+			}
+		}
+	})
+
+	sort.Strings(expired)
+
+	var events []domain.Event
+	for _, name := range expired {
+		events = append(events, s.record(ctx, domain.EventSnoozeEnd, name, fmt.Sprintf("%s snooze ended, guarding again", name)))
+	}
+
+	return events, err
+}
+
+// updateSnoozes reloads the snoozes from the store, applies the change and
+// saves them back
+func (s *MonitorService) updateSnoozes(change func(allowed map[string]time.Time)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.snoozes != nil {
+		stored, err := s.snoozes.Load()
+		if err != nil {
+			return err
+		}
+
+		s.allowed = make(map[string]time.Time, len(stored))
+		for _, snooze := range stored {
+			s.allowed[snooze.Interface] = snooze.Until
+		}
+	}
+
+	snapshot := make(map[string]time.Time, len(s.allowed))
+	for name, until := range s.allowed {
+		snapshot[name] = until
+	}
+
+	change(s.allowed)
+
+	if s.snoozes == nil || sameSnoozes(snapshot, s.allowed) {
+		return nil
+	}
+
+	stored := make([]domain.Snooze, 0, len(s.allowed))
+	for name, until := range s.allowed {
+		stored = append(stored, domain.Snooze{Interface: name, Until: until})
+	}
+	sort.Slice(stored, func(i, j int) bool { return stored[i].Interface < stored[j].Interface })
+
+	return s.snoozes.Save(stored)
+}
+
+func sameSnoozes(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, until := range a {
+		if other, ok := b[name]; !ok || !other.Equal(until) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *MonitorService) isAllowed(name string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.allowed[name]
+	return ok && until.After(now)
+}
+
+// record logs an event and adds it to the repository
+func (s *MonitorService) record(ctx context.Context, t domain.EventType, name, message string) domain.Event {
+	return s.recordEvent(ctx, domain.Event{Type: t, Interface: name, Message: message})
+}
+
+// recordEvent timestamps, logs and stores a fully built event
+func (s *MonitorService) recordEvent(ctx context.Context, evt domain.Event) domain.Event {
+	evt.Timestamp = s.now()
+
+	if cursor, err := s.logger.Log(ctx, evt); err == nil {
+		evt.Cursor = cursor
+	}
+
+	s.repo.Add(ctx, evt)
+
+	return evt
+}
+
+// operation bounds a single network operation by the configured timeout
+func (s *MonitorService) operation(ctx context.Context) (context.Context, context.CancelFunc) {
+	s.mu.Lock()
+	timeout := s.config.OperationTimeout
+	s.mu.Unlock()
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (s *MonitorService) check(ctx context.Context, name string) (domain.Status, error) {
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	return s.network.CheckInterface(ctx, name)
+}
+
+func (s *MonitorService) disable(ctx context.Context, name string) error {
+	if s.observeOnly {
+		return domain.ErrObserveOnly
+	}
+
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	return s.network.DisableInterface(ctx, name)
+}
+
+func (s *MonitorService) enable(ctx context.Context, name string) error {
+	if s.observeOnly {
+		return domain.ErrObserveOnly
+	}
+
+	ctx, cancel := s.operation(ctx)
+	defer cancel()
+	return s.network.EnableInterface(ctx, name)
+}
+
+// GetConfig returns the live configuration. It is only safe to read while no
+// tick runs, such as in one-shot commands; change it with UpdateConfig.
 func (s *MonitorService) GetConfig() *domain.Config {
 	return s.config
 }
 
-func (s *MonitorService) Restore() error {
-	return s.network.EnableInterface("awdl0")
+// Restore enables every guarded interface. It waits for a running tick, so
+// cancel that first. In observe-only mode there is nothing to restore.
+func (s *MonitorService) Restore(ctx context.Context) error {
+	s.work.Lock()
+	defer s.work.Unlock()
+
+	if s.observeOnly {
+		return nil
+	}
+
+	var err error
+	for _, name := range s.config.Interfaces {
+		err = errors.Join(err, s.enable(ctx, name))
+	}
+	return err
 }
@@ -1,8 +1,11 @@
 package services_test
 
 import (
+	"context"
+	"errors"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,10 +29,9 @@ func TestMonitorService_Tick_DisablesWhenUp(t *testing.T) {
 	logger := &MockLoggerPort{}
 	repo := &MockEventRepo{}
 
-	config := &domain.Config{PollingInterval: time.Second}
-	service := services.NewMonitorService(network, logger, repo, config)
+	service := services.NewMonitorService(network, logger, repo, domain.DefaultConfig())
 
-	event, err := service.Tick()
+	events, err := tickActions(service)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -37,7 +39,7 @@ func TestMonitorService_Tick_DisablesWhenUp(t *testing.T) {
 	if !disabledCalled {
 		t.Error("Expected DisableInterface to be called, but it wasn't")
 	}
-	if event == nil || event.Type != domain.EventDisable {
+	if len(events) != 1 || events[0].Type != domain.EventDisable || events[0].Interface != "awdl0" {
 		t.Error("Expected Disable event to be returned")
 	}
 }
@@ -55,9 +57,9 @@ func TestMonitorService_Tick_DoNothingWhenDown(t *testing.T) {
 		return nil
 	}
 
-	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, &domain.Config{})
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
 
-	event, err := service.Tick()
+	events, err := tickActions(service)
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
@@ -65,7 +67,1031 @@ func TestMonitorService_Tick_DoNothingWhenDown(t *testing.T) {
 	if disableCalled {
 		t.Error("Expected DisableInterface NOT to be called when status is DOWN")
 	}
-	if event != nil {
-		t.Errorf("Expected nil event when DOWN, got %v", event)
+	if len(events) != 0 {
+		t.Errorf("Expected no events when DOWN, got %v", events)
+	}
+}
+
+func TestMonitorService_Tick_ChecksEveryInterface(t *testing.T) {
+	var disabled []string
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusUp, nil
+		},
+		DisableFunc: func(name string) error {
+			disabled = append(disabled, name)
+			return nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.Interfaces = []string{"awdl0", "llw0"}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	events, err := tickActions(service)
+
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if len(events) != 2 || len(disabled) != 2 || disabled[0] != "awdl0" || disabled[1] != "llw0" {
+		t.Errorf("Expected both interfaces to be disabled, got %v", disabled)
+	}
+}
+
+func TestMonitorService_Allow_SkipsEnforcementUntilExpiry(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	disables := 0
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusUp, nil
+		},
+		DisableFunc: func(name string) error {
+			disables++
+			return nil
+		},
+	}
+	store := &MockSnoozeStore{}
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig()).
+		WithSnoozeStore(store).
+		WithClock(clock)
+
+	evt, err := service.Allow(context.Background(), "awdl0", 5*time.Minute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if evt.Type != domain.EventSnoozeStart {
+		t.Errorf("Expected a snooze start event, got %v", evt.Type)
+	}
+	if len(store.Snoozes) != 1 || !store.Snoozes[0].Until.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("Expected the snooze to be persisted, got %v", store.Snoozes)
+	}
+
+	now = now.Add(4 * time.Minute)
+	events, _ := tickActions(service)
+	if disables != 0 || len(events) != 0 {
+		t.Errorf("Expected no enforcement during the snooze, got %d disables and %v", disables, events)
+	}
+	if snoozes := service.Snoozes(); len(snoozes) != 1 {
+		t.Errorf("Expected one active snooze, got %v", snoozes)
+	}
+
+	now = now.Add(time.Minute)
+	events, _ = tickActions(service)
+	if len(events) != 2 || events[0].Type != domain.EventSnoozeEnd || events[1].Type != domain.EventDisable {
+		t.Errorf("Expected the snooze to end and awdl0 to be disabled, got %v", events)
+	}
+	if len(store.Snoozes) != 0 {
+		t.Errorf("Expected the expired snooze to be removed from the store, got %v", store.Snoozes)
+	}
+}
+
+func TestMonitorService_Allow_PicksUpSnoozesFromStore(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusUp, nil
+		},
+		DisableFunc: func(name string) error {
+			t.Error("Expected no disable while another process snoozed awdl0")
+			return nil
+		},
+	}
+	store := &MockSnoozeStore{Snoozes: []domain.Snooze{{Interface: "awdl0", Until: now.Add(time.Minute)}}}
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig()).
+		WithSnoozeStore(store).
+		WithClock(func() time.Time { return now })
+
+	if _, err := service.Tick(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if store.Saves != 0 {
+		t.Errorf("Expected an unchanged store not to be rewritten, got %d saves", store.Saves)
+	}
+}
+
+func TestMonitorService_EndSnooze(t *testing.T) {
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusDown, nil
+		},
+	}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
+
+	if evt, err := service.EndSnooze(context.Background(), "awdl0"); evt != nil || err != nil {
+		t.Errorf("Expected nothing to end, got %v, %v", evt, err)
+	}
+
+	if _, err := service.Allow(context.Background(), "awdl0", 0); err == nil {
+		t.Error("Expected a zero duration to be rejected")
+	}
+
+	_, _ = service.Allow(context.Background(), "awdl0", time.Minute)
+	evt, err := service.EndSnooze(context.Background(), "awdl0")
+	if err != nil || evt == nil || evt.Type != domain.EventSnoozeEnd {
+		t.Errorf("Expected a snooze end event, got %v, %v", evt, err)
+	}
+	if snoozes := service.Snoozes(); len(snoozes) != 0 {
+		t.Errorf("Expected no active snoozes, got %v", snoozes)
+	}
+}
+
+func TestMonitorService_Tick_FollowsSchedule(t *testing.T) {
+	now := time.Date(2024, 5, 6, 8, 59, 0, 0, time.UTC) // Monday
+
+	disables, enables := 0, 0
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusUp, nil
+		},
+		DisableFunc: func(name string) error {
+			disables++
+			return nil
+		},
+		EnableFunc: func(name string) error {
+			enables++
+			return nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.Schedule = &domain.Schedule{
+		Timezone: "UTC",
+		Default:  domain.ModeObserve,
+		Rules: []domain.ScheduleRule{
+			{Mode: domain.ModeEnforce, Days: []string{"mon-fri"}, Start: "09:00", End: "18:00"},
+		},
+	}
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+		WithClock(func() time.Time { return now })
+
+	events, _ := tickActions(service)
+	if disables != 0 || len(events) != 1 || events[0].Type != domain.EventSchedule {
+		t.Errorf("Expected only the initial schedule to be logged while observing, got %v", events)
+	}
+
+	events, _ = tickActions(service)
+	if len(events) != 0 {
+		t.Errorf("Expected nothing to be logged without a transition, got %v", events)
+	}
+
+	now = now.Add(time.Minute)
+	events, _ = tickActions(service)
+	if disables != 1 || len(events) != 2 || events[0].Type != domain.EventSchedule || events[1].Type != domain.EventDisable {
+		t.Errorf("Expected the transition to enforce and a disable, got %v", events)
+	}
+
+	state, ok := service.Schedule()
+	if !ok || state.Mode != domain.ModeEnforce || !state.Next.Equal(time.Date(2024, 5, 6, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected enforcement until 18:00, got %+v", state)
+	}
+
+	now = time.Date(2024, 5, 6, 18, 0, 0, 0, time.UTC)
+	events, _ = tickActions(service)
+	if enables != 1 || len(events) != 2 || events[1].Type != domain.EventEnable {
+		t.Errorf("Expected the transition to observe to enable awdl0, got %v", events)
+	}
+}
+
+func TestMonitorService_Tick_ProcessRulesOverrideSchedule(t *testing.T) {
+	disables, enables := 0, 0
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return domain.StatusUp, nil
+		},
+		DisableFunc: func(name string) error {
+			disables++
+			return nil
+		},
+		EnableFunc: func(name string) error {
+			enables++
+			return nil
+		},
+	}
+	processes := &MockProcessPort{Running: []string{"launchd", "Finder"}}
+
+	config := domain.DefaultConfig()
+	config.ProcessRules = []domain.ProcessRule{
+		{Name: "Sidecar", Processes: []string{"SidecarRelay"}, Mode: domain.ModeObserve},
+		{Name: "Calls", Processes: []string{"zoom.us"}, Mode: domain.ModeEnforce},
+	}
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+		WithProcesses(processes)
+
+	events, _ := tickActions(service)
+	if disables != 1 || len(events) != 1 {
+		t.Errorf("Expected enforcement without a matching rule, got %v", events)
+	}
+
+	processes.Running = append(processes.Running, "sidecarrelay", "zoom.us")
+	events, _ = tickActions(service)
+	if len(events) != 2 || events[0].Type != domain.EventRule || events[1].Type != domain.EventEnable || enables != 1 {
+		t.Errorf("Expected the first matching rule to allow awdl0, got %v", events)
+	}
+	if rule, ok := service.ActiveRule(); !ok || rule.Name != "Sidecar" {
+		t.Errorf("Expected Sidecar to be the active rule, got %v", rule)
+	}
+	if disables != 1 {
+		t.Errorf("Expected no enforcement while observing, got %d disables", disables)
+	}
+
+	processes.Err = errors.New("ps failed")
+	if _, err := service.Tick(context.Background()); err == nil {
+		t.Error("Expected the process listing error to be returned")
+	}
+	if _, ok := service.ActiveRule(); !ok {
+		t.Error("Expected the rule to stay active while processes cannot be listed")
+	}
+
+	processes.Err = nil
+	processes.Running = []string{"launchd"}
+	events, _ = tickActions(service)
+	if len(events) != 2 || events[0].Type != domain.EventRule || events[1].Type != domain.EventDisable {
+		t.Errorf("Expected the rule to end and enforcement to resume, got %v", events)
+	}
+}
+
+func TestMonitorService_Tick_Debounce(t *testing.T) {
+	up, down := domain.StatusUp, domain.StatusDown
+
+	tests := []struct {
+		name       string
+		readings   int
+		duration   time.Duration
+		hysteresis int
+		statuses   []domain.Status
+		// disabledAt lists the ticks that disable the interface
+		disabledAt []int
+	}{
+		{
+			name:       "Immediate",
+			statuses:   []domain.Status{up, down, up},
+			disabledAt: []int{0, 2},
+		},
+		{
+			name:       "Three Readings",
+			readings:   3,
+			statuses:   []domain.Status{up, up, up, up},
+			disabledAt: []int{2},
+		},
+		{
+			name:       "Burst Ends In Time",
+			readings:   3,
+			statuses:   []domain.Status{up, up, down, up, up},
+			disabledAt: nil,
+		},
+		{
+			name:       "Minimum Time",
+			duration:   2 * time.Second,
+			statuses:   []domain.Status{up, up, up, up},
+			disabledAt: []int{2},
+		},
+		{
+			name:       "Readings And Time",
+			readings:   2,
+			duration:   3 * time.Second,
+			statuses:   []domain.Status{up, up, up, up, up},
+			disabledAt: []int{3},
+		},
+		{
+			name:       "Hysteresis Keeps The Streak",
+			readings:   3,
+			hysteresis: 1,
+			statuses:   []domain.Status{up, up, down, up},
+			disabledAt: []int{3},
+		},
+		{
+			name:       "Hysteresis Exhausted",
+			readings:   3,
+			hysteresis: 1,
+			statuses:   []domain.Status{up, up, down, down, up},
+			disabledAt: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			tick := 0
+
+			var disabledAt []int
+			network := &MockNetworkPort{
+				CheckFunc: func(name string) (domain.Status, error) {
+					return tt.statuses[tick], nil
+				},
+				DisableFunc: func(name string) error {
+					disabledAt = append(disabledAt, tick)
+					return nil
+				},
+			}
+
+			config := domain.DefaultConfig()
+			config.DebounceReadings = tt.readings
+			config.DebounceDuration = tt.duration
+			config.HysteresisReadings = tt.hysteresis
+
+			service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+				WithClock(func() time.Time { return now })
+
+			for tick = range tt.statuses {
+				if _, err := service.Tick(context.Background()); err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				now = now.Add(time.Second)
+			}
+
+			if len(disabledAt) != len(tt.disabledAt) {
+				t.Fatalf("Expected disables at %v, got %v", tt.disabledAt, disabledAt)
+			}
+			for i := range disabledAt {
+				if disabledAt[i] != tt.disabledAt[i] {
+					t.Errorf("Expected disables at %v, got %v", tt.disabledAt, disabledAt)
+				}
+			}
+		})
+	}
+}
+
+func TestMonitorService_Tick_FlapDetection(t *testing.T) {
+	tests := []struct {
+		strategy string
+		// disables is how many of the ten flapping ticks disable awdl0
+		disables int
+		interval time.Duration
+	}{
+		{strategy: domain.FlapAggressive, disables: 10, interval: domain.MinPollingInterval},
+		{strategy: domain.FlapBackoff, disables: 6, interval: time.Second},
+		{strategy: domain.FlapStop, disables: 4, interval: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			status := domain.StatusUp
+
+			disables := 0
+			network := &MockNetworkPort{
+				CheckFunc: func(name string) (domain.Status, error) {
+					return status, nil
+				},
+				DisableFunc: func(name string) error {
+					disables++
+					return nil
+				},
+			}
+
+			config := domain.DefaultConfig()
+			config.FlapThreshold = 3
+			config.FlapWindow = time.Minute
+			config.FlapStrategy = tt.strategy
+
+			service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+				WithClock(func() time.Time { return now })
+
+			var all []domain.Event
+			for i := 0; i < 10; i++ {
+				events, _ := tickActions(service)
+				all = append(all, events...)
+				now = now.Add(time.Second)
+			}
+
+			if disables != tt.disables {
+				t.Errorf("Expected %d disables, got %d", tt.disables, disables)
+			}
+			if !hasEvent(all, domain.EventFlapDetected) {
+				t.Errorf("Expected a FlapDetected event, got %v", all)
+			}
+			if flapping := service.Flapping(); len(flapping) != 1 || flapping[0] != "awdl0" {
+				t.Errorf("Expected awdl0 to be flapping, got %v", flapping)
+			}
+			if got := service.PollInterval(); got != tt.interval {
+				t.Errorf("Expected a poll interval of %v, got %v", tt.interval, got)
+			}
+
+			// Once awdl0 stays down for a whole window, the flapping clears
+			status = domain.StatusDown
+			now = now.Add(time.Minute)
+			events, _ := tickActions(service)
+			if !hasEvent(events, domain.EventFlapCleared) || len(service.Flapping()) != 0 {
+				t.Errorf("Expected a FlapCleared event, got %v", events)
+			}
+		})
+	}
+}
+
+func hasEvent(events []domain.Event, eventType domain.EventType) bool {
+	for _, e := range events {
+		if e.Type == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMonitorService_PollInterval_Adaptive(t *testing.T) {
+	up, down := domain.StatusUp, domain.StatusDown
+	status := down
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return status, nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.AdaptivePolling = true
+	config.AdaptiveCeiling = 5 * time.Second
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	steps := []struct {
+		status domain.Status
+		want   time.Duration
+	}{
+		{down, time.Second},
+		{down, 2 * time.Second},
+		{down, 4 * time.Second},
+		{down, 5 * time.Second},
+		{up, domain.MinPollingInterval},
+		{down, time.Second},
+		{down, 2 * time.Second},
+	}
+
+	for i, step := range steps {
+		status = step.status
+		if _, err := service.Tick(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := service.PollInterval(); got != step.want {
+			t.Errorf("Tick %d: expected %v, got %v", i, step.want, got)
+		}
+	}
+
+	config.AdaptivePolling = false
+	if _, err := service.Tick(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := service.PollInterval(); got != config.PollingInterval {
+		t.Errorf("Expected the configured interval once adaptive polling is off, got %v", got)
+	}
+}
+
+func TestMonitorService_PollInterval_AdaptiveObserveOnly(t *testing.T) {
+	status := domain.StatusDown
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return status, nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.AdaptivePolling = true
+	config.AdaptiveCeiling = 5 * time.Second
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+		WithObserveOnly()
+
+	for i := 0; i < 3; i++ {
+		if _, err := service.Tick(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	status = domain.StatusUp
+	if _, err := service.Tick(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := service.PollInterval(); got != domain.MinPollingInterval {
+		t.Errorf("Expected a reactivation seen in observe-only mode to speed polling up, got %v", got)
+	}
+}
+
+func TestMonitorService_NextPoll_Jitter(t *testing.T) {
+	config := domain.DefaultConfig()
+	service := services.NewMonitorService(&MockNetworkPort{}, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	if got := service.NextPoll(); got != config.PollingInterval {
+		t.Errorf("Expected no jitter by default, got %v", got)
+	}
+
+	config.PollJitter = 200 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		got := service.NextPoll()
+		if got < config.PollingInterval || got > config.PollingInterval+config.PollJitter {
+			t.Fatalf("Expected %v to be within the jitter", got)
+		}
+	}
+}
+
+func TestMonitorService_Tick_RecordsUpDuration(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	status := domain.StatusUp
+
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return status, nil
+		},
+		DisableFunc: func(name string) error {
+			now = now.Add(40 * time.Millisecond)
+			status = domain.StatusDown
+			return nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.DebounceReadings = 2
+
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+		WithClock(func() time.Time { return now })
+
+	if _, err := service.Tick(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	now = now.Add(time.Second)
+	events, err := tickActions(service)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(events) != 1 || events[0].UpDuration != 1040*time.Millisecond {
+		t.Fatalf("Expected UP from the first reading until the verified disable, got %v", events)
+	}
+	if strings.Contains(events[0].Message, "still UP") {
+		t.Errorf("Expected the disable to be verified, got %q", events[0].Message)
+	}
+}
+
+// tickActions runs a tick and drops the Check events recording state changes
+func tickActions(service *services.MonitorService) ([]domain.Event, error) {
+	events, err := service.Tick(context.Background())
+
+	var actions []domain.Event
+	for _, e := range events {
+		if e.Type != domain.EventCheck {
+			actions = append(actions, e)
+		}
+	}
+	return actions, err
+}
+
+func TestMonitorService_Tick_RecordsTransitions(t *testing.T) {
+	status := domain.StatusDown
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return status, nil
+		},
+	}
+
+	config := domain.DefaultConfig()
+	config.Schedule = &domain.Schedule{Default: domain.ModeObserve}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	steps := []struct {
+		status domain.Status
+		checks []domain.Status
+	}{
+		{domain.StatusDown, []domain.Status{domain.StatusDown}},
+		{domain.StatusDown, nil},
+		// Brought up by someone else while observing
+		{domain.StatusUp, []domain.Status{domain.StatusUp}},
+		{domain.StatusUp, nil},
+		{domain.StatusDown, []domain.Status{domain.StatusDown}},
+	}
+
+	for i, step := range steps {
+		status = step.status
+		events, err := service.Tick(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var checks []domain.Status
+		for _, e := range events {
+			if e.Type == domain.EventCheck {
+				checks = append(checks, e.Status)
+			}
+		}
+		if len(checks) != len(step.checks) || (len(checks) > 0 && checks[0] != step.checks[0]) {
+			t.Errorf("Tick %d: expected checks %v, got %v", i, step.checks, checks)
+		}
+	}
+}
+
+func TestMonitorService_Tick_RecordsOwnDisableAsTransition(t *testing.T) {
+	status := domain.StatusUp
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			return status, nil
+		},
+		DisableFunc: func(name string) error {
+			status = domain.StatusDown
+			return nil
+		},
+	}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
+
+	events, err := service.Tick(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []domain.EventType{domain.EventCheck, domain.EventDisable, domain.EventCheck}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, events)
+	}
+	for i, e := range events {
+		if e.Type != want[i] {
+			t.Errorf("Expected %v, got %v", want, events)
+		}
+	}
+	if events[2].Status != domain.StatusDown {
+		t.Errorf("Expected the last check to see awdl0 DOWN, got %v", events[2].Status)
+	}
+}
+
+func TestMonitorService_Tick_CheckFailures(t *testing.T) {
+	var checkErr error
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) {
+			if checkErr != nil {
+				return domain.StatusUnknown, checkErr
+			}
+			return domain.StatusDown, nil
+		},
+		DisableFunc: func(name string) error {
+			t.Error("Expected nothing to be disabled")
+			return nil
+		},
+	}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
+
+	notFound := &domain.CommandError{Command: "ifconfig awdl0", ExitCode: 1, Kind: domain.ErrInterfaceNotFound}
+	denied := &domain.CommandError{Command: "ifconfig awdl0", ExitCode: 1, Kind: domain.ErrPermissionDenied}
+	failed := &domain.CommandError{Command: "ifconfig awdl0", ExitCode: 2, Stderr: "boom"}
+
+	steps := []struct {
+		err     error
+		message string
+		status  domain.Status
+		failed  bool
+	}{
+		{notFound, "awdl0 is Absent", domain.StatusAbsent, false},
+		{notFound, "", "", false},
+		{denied, "awdl0 is Unknown: permission denied", domain.StatusUnknown, true},
+		{failed, "awdl0 is Unknown: ifconfig awdl0: exit code 2: boom", domain.StatusUnknown, true},
+		{nil, "awdl0 is DOWN", domain.StatusDown, false},
+	}
+
+	for i, step := range steps {
+		checkErr = step.err
+		events, err := service.Tick(context.Background())
+
+		if (err != nil) != step.failed {
+			t.Errorf("Tick %d: unexpected error %v", i, err)
+		}
+		if step.message == "" {
+			if len(events) != 0 {
+				t.Errorf("Tick %d: expected no events, got %v", i, events)
+			}
+			continue
+		}
+		if len(events) != 1 || events[0].Message != step.message || events[0].Status != step.status {
+			t.Errorf("Tick %d: expected %q (%s), got %v", i, step.message, step.status, events)
+		}
+	}
+}
+
+func TestMonitorService_StateSince(t *testing.T) {
+	now := time.Date(2025, 3, 2, 10, 0, 0, 0, time.UTC)
+	status := domain.StatusDown
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) { return status, nil },
+	}
+
+	config := domain.DefaultConfig()
+	config.Schedule = &domain.Schedule{Default: domain.ModeObserve}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).
+		WithClock(func() time.Time { return now })
+
+	if !service.StateSince("awdl0").IsZero() {
+		t.Error("Expected no state before the first check")
+	}
+
+	start := now
+	for i := 0; i < 3; i++ {
+		service.Tick(context.Background())
+		now = now.Add(time.Second)
+	}
+	if got := service.StateSince("awdl0"); !got.Equal(start) {
+		t.Errorf("Expected DOWN since %v, got %v", start, got)
+	}
+
+	status = domain.StatusUp
+	changed := now
+	service.Tick(context.Background())
+	now = now.Add(time.Second)
+	service.Tick(context.Background())
+	if got := service.StateSince("awdl0"); !got.Equal(changed) {
+		t.Errorf("Expected UP since %v, got %v", changed, got)
+	}
+}
+
+func TestMonitorService_SetInterfaces(t *testing.T) {
+	var enabled []string
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) { return domain.StatusDown, nil },
+		EnableFunc: func(name string) error {
+			enabled = append(enabled, name)
+			return nil
+		},
+	}
+	config := domain.DefaultConfig()
+	config.Interfaces = []string{"awdl0", "llw0"}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	if _, err := service.SetInterfaces(context.Background(), nil); err == nil {
+		t.Error("Expected an empty selection to be rejected")
+	}
+	if _, err := service.SetInterfaces(context.Background(), []string{"bad name"}); err == nil {
+		t.Error("Expected an invalid name to be rejected")
+	}
+	if len(config.Interfaces) != 2 {
+		t.Fatalf("Expected rejected selections to keep the config, got %v", config.Interfaces)
+	}
+
+	events, err := service.SetInterfaces(context.Background(), []string{"awdl0", "en5"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Join(config.Interfaces, ",") != "awdl0,en5" {
+		t.Errorf("Expected the config to be updated, got %v", config.Interfaces)
+	}
+	if len(enabled) != 1 || enabled[0] != "llw0" {
+		t.Errorf("Expected only llw0 to be enabled again, got %v", enabled)
+	}
+	if len(events) != 1 || events[0].Type != domain.EventEnable || events[0].Interface != "llw0" {
+		t.Errorf("Expected an Enable event for llw0, got %v", events)
+	}
+}
+
+// recordingAllowList records every allow list and fails with err
+type recordingAllowList struct {
+	lists []string
+	err   error
+}
+
+func (r *recordingAllowList) AllowInterfaces(_ context.Context, names []string) error {
+	r.lists = append(r.lists, strings.Join(names, ","))
+	return r.err
+}
+
+func TestMonitorService_AllowList(t *testing.T) {
+	var enabled []string
+	network := &MockNetworkPort{
+		EnableFunc: func(name string) error {
+			enabled = append(enabled, name)
+			return nil
+		},
+	}
+	config := domain.DefaultConfig()
+	config.Interfaces = []string{"awdl0", "llw0"}
+	allowList := &recordingAllowList{}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config).WithAllowList(allowList)
+
+	if _, err := service.SetInterfaces(context.Background(), []string{"awdl0", "en5"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// llw0 stays allowed until it has been enabled again
+	if strings.Join(allowList.lists, "; ") != "awdl0,en5,llw0; awdl0,en5" {
+		t.Errorf("Expected the dropped interface to be allowed until released, got %q", allowList.lists)
+	}
+
+	allowList.err = errors.New("rejected by helper")
+	enabled = nil
+	if _, err := service.SetInterfaces(context.Background(), []string{"lo"}); err == nil {
+		t.Error("Expected a refused allow list to fail")
+	}
+	if strings.Join(config.Interfaces, ",") != "awdl0,en5" || len(enabled) != 0 {
+		t.Errorf("Expected a refused allow list to change nothing, got %v and enabled %v", config.Interfaces, enabled)
+	}
+}
+
+func TestMonitorService_UpdateConfig(t *testing.T) {
+	var enabled []string
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) { return domain.StatusDown, nil },
+		EnableFunc: func(name string) error {
+			enabled = append(enabled, name)
+			return nil
+		},
+	}
+	config := domain.DefaultConfig()
+	config.Interfaces = []string{"awdl0", "llw0"}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	invalid := config.Clone()
+	invalid.PollingInterval = time.Millisecond
+	if _, err := service.UpdateConfig(context.Background(), invalid); err == nil {
+		t.Error("Expected an invalid config to be rejected")
+	}
+
+	edited := domain.DefaultConfig()
+	edited.PollingInterval = 3 * time.Second
+	events, err := service.UpdateConfig(context.Background(), edited)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if service.PollInterval() != 3*time.Second {
+		t.Errorf("Expected the new polling interval, got %v", service.PollInterval())
+	}
+	if len(enabled) != 1 || enabled[0] != "llw0" {
+		t.Errorf("Expected the dropped llw0 to be enabled again, got %v", enabled)
+	}
+	if len(events) != 1 || events[0].Type != domain.EventEnable {
+		t.Errorf("Expected an Enable event, got %v", events)
+	}
+
+	// The monitor keeps its own copy
+	edited.Interfaces[0] = "en9"
+	if config.Interfaces[0] != "awdl0" {
+		t.Errorf("Expected later edits not to reach the monitor, got %v", config.Interfaces)
+	}
+}
+
+func TestMonitorService_ChangesWaitForTick(t *testing.T) {
+	changes := map[string]func(*services.MonitorService) error{
+		"SetInterfaces": func(s *services.MonitorService) error {
+			_, err := s.SetInterfaces(context.Background(), []string{"llw0"})
+			return err
+		},
+		"ToggleInterface": func(s *services.MonitorService) error {
+			_, err := s.ToggleInterface(context.Background(), "awdl0")
+			return err
+		},
+		"Allow": func(s *services.MonitorService) error {
+			_, err := s.Allow(context.Background(), "awdl0", time.Minute)
+			return err
+		},
+		"EndSnooze": func(s *services.MonitorService) error {
+			_, err := s.EndSnooze(context.Background(), "awdl0")
+			return err
+		},
+	}
+
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			checking := make(chan struct{}, 1)
+			release := make(chan struct{})
+			blocked := true
+			network := &MockNetworkPort{
+				CheckFunc: func(string) (domain.Status, error) {
+					if blocked {
+						blocked = false
+						checking <- struct{}{}
+						<-release
+					}
+					return domain.StatusDown, nil
+				},
+			}
+			service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
+
+			ticked := make(chan error)
+			go func() {
+				_, err := service.Tick(context.Background())
+				ticked <- err
+			}()
+			<-checking
+
+			changed := make(chan error)
+			go func() { changed <- change(service) }()
+
+			select {
+			case <-changed:
+				t.Fatal("Expected the change to wait for the running tick")
+			case <-time.After(20 * time.Millisecond):
+			}
+
+			close(release)
+			if err := <-ticked; err != nil {
+				t.Fatalf("Unexpected tick error: %v", err)
+			}
+			if err := <-changed; err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+// hangingNetwork never answers a check before the context is done, like an
+// ifconfig that hangs
+type hangingNetwork struct {
+	MockNetworkPort
+	enabled chan string
+}
+
+func (h *hangingNetwork) CheckInterface(ctx context.Context, name string) (domain.Status, error) {
+	<-ctx.Done()
+	return domain.StatusUnknown, ctx.Err()
+}
+
+func (h *hangingNetwork) EnableInterface(_ context.Context, name string) error {
+	h.enabled <- name
+	return nil
+}
+
+func TestMonitorService_Tick_OperationTimeout(t *testing.T) {
+	config := domain.DefaultConfig()
+	config.OperationTimeout = 10 * time.Millisecond
+	service := services.NewMonitorService(&hangingNetwork{}, &MockLoggerPort{}, &MockEventRepo{}, config)
+
+	events, err := service.Tick(context.Background())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the check to time out, got %v", err)
+	}
+	if len(events) != 1 || events[0].Status != domain.StatusUnknown {
+		t.Errorf("Expected awdl0 to be Unknown after the timeout, got %v", events)
+	}
+}
+
+func TestMonitorService_Restore_AfterCancel(t *testing.T) {
+	network := &hangingNetwork{enabled: make(chan string, 1)}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	type result struct {
+		events []domain.Event
+		err    error
+	}
+	ticked := make(chan result)
+	go func() {
+		events, err := service.Tick(ctx)
+		ticked <- result{events, err}
+	}()
+
+	cancel()
+	r := <-ticked
+	if !errors.Is(r.err, context.Canceled) {
+		t.Errorf("Expected the tick to be cancelled, got %v", r.err)
+	}
+	if len(r.events) != 0 {
+		t.Errorf("Expected a cancelled check to record nothing, got %v", r.events)
+	}
+
+	if err := service.Restore(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if name := <-network.enabled; name != "awdl0" {
+		t.Errorf("Expected awdl0 to be restored, got %s", name)
+	}
+}
+
+func TestMonitorService_ObserveOnly(t *testing.T) {
+	status := domain.StatusUp
+	network := &MockNetworkPort{
+		CheckFunc: func(name string) (domain.Status, error) { return status, nil },
+		DisableFunc: func(name string) error {
+			t.Error("Expected DisableInterface not to be called")
+			return nil
+		},
+		EnableFunc: func(name string) error {
+			t.Error("Expected EnableInterface not to be called")
+			return nil
+		},
+	}
+	service := services.NewMonitorService(network, &MockLoggerPort{}, &MockEventRepo{}, domain.DefaultConfig()).
+		WithObserveOnly()
+
+	var would int
+	for _, s := range []domain.Status{domain.StatusUp, domain.StatusUp, domain.StatusDown, domain.StatusUp} {
+		status = s
+		events, err := service.Tick(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, e := range events {
+			if e.Type == domain.EventWouldDisable {
+				would++
+			}
+		}
+	}
+	if would != 2 {
+		t.Errorf("Expected one WouldDisable per UP period, got %d", would)
+	}
+
+	if _, err := service.ToggleInterface(context.Background(), "awdl0"); !errors.Is(err, domain.ErrObserveOnly) {
+		t.Errorf("Expected toggling to be refused, got %v", err)
+	}
+	if _, err := service.Allow(context.Background(), "awdl0", time.Minute); err != nil {
+		t.Errorf("Expected snoozing to work, got %v", err)
+	}
+	if err := service.Restore(context.Background()); err != nil {
+		t.Errorf("Unexpected error: %v", err)
 	}
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"context"
 	"math"
+	"sort"
 	"time"
 
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
@@ -16,14 +18,16 @@ func NewStatsService(r ports.EventRepository) *StatsService {
 	return &StatsService{repo: r}
 }
 
-// GetHistogram generates a fixed number of buckets for the given duration
-func (s *StatsService) GetHistogram(duration time.Duration, numBuckets int) []domain.Bucket {
-	return s.GetHistogramAt(time.Now(), duration, numBuckets)
+// GetHistogram counts the reactivations of the given duration in a fixed
+// number of buckets
+func (s *StatsService) GetHistogram(ctx context.Context, duration time.Duration, numBuckets int) []domain.Bucket {
+	return s.GetHistogramAt(ctx, time.Now(), duration, numBuckets)
 }
 
-// GetHistogramAt generates histogram relative to a specific time (useful for testing)
-func (s *StatsService) GetHistogramAt(now time.Time, duration time.Duration, numBuckets int) []domain.Bucket {
-	events := s.repo.GetRecent(duration)
+// GetHistogramAt generates histogram relative to a specific time (useful for testing).
+// Only disables count, or the disables observe-only mode would have done.
+func (s *StatsService) GetHistogramAt(ctx context.Context, now time.Time, duration time.Duration, numBuckets int) []domain.Bucket {
+	events := s.repo.GetRecent(ctx, duration)
 
 	buckets := make([]domain.Bucket, numBuckets)
 
@@ -38,6 +42,9 @@ func (s *StatsService) GetHistogramAt(now time.Time, duration time.Duration, num
 	}
 
 	for _, evt := range events {
+		if evt.Type != domain.EventDisable && evt.Type != domain.EventWouldDisable {
+			continue
+		}
 		if evt.Timestamp.Before(startTime) || evt.Timestamp.After(now) {
 			continue
 		}
@@ -53,7 +60,121 @@ func (s *StatsService) GetHistogramAt(now time.Time, duration time.Duration, num
 	return buckets
 }
 
+// GetSummary counts the events of the given duration by type
+func (s *StatsService) GetSummary(ctx context.Context, duration time.Duration) domain.Summary {
+	var summary domain.Summary
+
+	for _, evt := range s.repo.GetRecent(ctx, duration) {
+		summary.Total++
+
+		switch evt.Type {
+		case domain.EventDisable:
+			summary.Disables++
+		case domain.EventEnable:
+			summary.Enables++
+		case domain.EventWouldDisable:
+			summary.WouldDisables++
+		}
+
+		if evt.Timestamp.After(summary.Last) {
+			summary.Last = evt.Timestamp
+		}
+	}
+
+	return summary
+}
+
+// GetExposure sums the UP durations of the disables in the given duration,
+// per clock hour
+func (s *StatsService) GetExposure(ctx context.Context, duration time.Duration) domain.Exposure {
+	return s.GetExposureAt(ctx, time.Now(), duration)
+}
+
+// GetExposureAt computes the exposure relative to a specific time. Each UP
+// period counts towards the hour it was disabled in.
+func (s *StatsService) GetExposureAt(ctx context.Context, now time.Time, duration time.Duration) domain.Exposure {
+	var exposure domain.Exposure
+
+	first := now.Add(-duration).Truncate(time.Hour)
+	for hour := first; !hour.After(now); hour = hour.Add(time.Hour) {
+		exposure.Hours = append(exposure.Hours, domain.HourlyExposure{Hour: hour})
+	}
+
+	for _, evt := range s.repo.GetRecent(ctx, duration) {
+		if evt.Type != domain.EventDisable || evt.Timestamp.After(now) {
+			continue
+		}
+
+		exposure.Disables++
+		exposure.Total += evt.UpDuration
+		exposure.Longest = max(exposure.Longest, evt.UpDuration)
+
+		index := int(evt.Timestamp.Truncate(time.Hour).Sub(first) / time.Hour)
+		if index >= 0 && index < len(exposure.Hours) {
+			exposure.Hours[index].Up += evt.UpDuration
+		}
+	}
+
+	return exposure
+}
+
+// GetTimeline splits the given duration into columns and returns the state
+// of the interface during each of them
+func (s *StatsService) GetTimeline(ctx context.Context, name string, duration time.Duration, columns int) []domain.Status {
+	return s.GetTimelineAt(ctx, time.Now(), name, duration, columns)
+}
+
+// timelineLookback is how far before the window the timeline looks for the
+// state the interface was in when the window started
+const timelineLookback = 24 * time.Hour
+
+// GetTimelineAt builds the timeline relative to a specific time. A column is
+// UP if the interface was UP at any point of it, so short reactivations stay
+// visible. The window starts in the state of the last transition before it;
+// columns before any recorded transition are Unknown.
+func (s *StatsService) GetTimelineAt(ctx context.Context, now time.Time, name string, duration time.Duration, columns int) []domain.Status {
+	timeline := make([]domain.Status, columns)
+	for i := range timeline {
+		timeline[i] = domain.StatusUnknown
+	}
+	if columns <= 0 || duration <= 0 {
+		return timeline
+	}
+
+	var checks []domain.Event
+	for _, evt := range s.repo.GetRecent(ctx, duration+timelineLookback) {
+		if evt.Type == domain.EventCheck && evt.Interface == name && evt.Status != "" && !evt.Timestamp.After(now) {
+			checks = append(checks, evt)
+		}
+	}
+	sort.SliceStable(checks, func(i, j int) bool { return checks[i].Timestamp.Before(checks[j].Timestamp) })
+
+	start := now.Add(-duration)
+	column := func(t time.Time) int {
+		index := int(float64(t.Sub(start)) / float64(duration) * float64(columns))
+		return min(max(index, 0), columns-1)
+	}
+
+	for i, evt := range checks {
+		until := now
+		if i+1 < len(checks) {
+			until = checks[i+1].Timestamp
+		}
+		if until.Before(start) {
+			continue
+		}
+
+		for c := column(evt.Timestamp); c <= column(until); c++ {
+			if timeline[c] != domain.StatusUp {
+				timeline[c] = evt.Status
+			}
+		}
+	}
+
+	return timeline
+}
+
 // GetRecentEvents returns raw events from the repository for the given duration
-func (s *StatsService) GetRecentEvents(duration time.Duration) []domain.Event {
-	return s.repo.GetRecent(duration)
+func (s *StatsService) GetRecentEvents(ctx context.Context, duration time.Duration) []domain.Event {
+	return s.repo.GetRecent(ctx, duration)
 }
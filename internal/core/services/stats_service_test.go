@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/domain"
 	"github.com/anderson-oki/awdl0-disabler/internal/core/services"
 	"testing"
@@ -12,17 +13,19 @@ func TestStatsService_GetHistogram(t *testing.T) {
 	repo := &MockEventRepo{
 		GetRecentFunc: func(d time.Duration) []domain.Event {
 			return []domain.Event{
-				{Timestamp: now.Add(-5 * time.Minute)},
-				{Timestamp: now.Add(-5 * time.Minute)},
-				{Timestamp: now.Add(-55 * time.Minute)},
-				{Timestamp: now.Add(-2 * time.Hour)},
+				{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventDisable},
+				{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventWouldDisable},
+				{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventCheck, Status: domain.StatusUp},
+				{Timestamp: now.Add(-55 * time.Minute), Type: domain.EventDisable},
+				{Timestamp: now.Add(-55 * time.Minute), Type: domain.EventSnoozeStart},
+				{Timestamp: now.Add(-2 * time.Hour), Type: domain.EventDisable},
 			}
 		},
 	}
 
 	service := services.NewStatsService(repo)
 
-	buckets := service.GetHistogramAt(now, 1*time.Hour, 60)
+	buckets := service.GetHistogramAt(context.Background(), now, 1*time.Hour, 60)
 
 	if len(buckets) != 60 {
 		t.Errorf("Expected 60 buckets, got %d", len(buckets))
@@ -38,3 +41,106 @@ func TestStatsService_GetHistogram(t *testing.T) {
 		t.Errorf("Expected 0 events in bucket 0, got %d", buckets[0].Count)
 	}
 }
+
+func TestStatsService_GetSummary(t *testing.T) {
+	now := time.Now()
+	repo := &MockEventRepo{
+		GetRecentFunc: func(d time.Duration) []domain.Event {
+			return []domain.Event{
+				{Timestamp: now.Add(-3 * time.Minute), Type: domain.EventDisable},
+				{Timestamp: now.Add(-1 * time.Minute), Type: domain.EventEnable},
+				{Timestamp: now.Add(-2 * time.Minute), Type: domain.EventDisable},
+				{Timestamp: now.Add(-4 * time.Minute), Type: domain.EventCheck},
+			}
+		},
+	}
+
+	summary := services.NewStatsService(repo).GetSummary(context.Background(), time.Hour)
+
+	if summary.Total != 4 {
+		t.Errorf("Expected 4 events, got %d", summary.Total)
+	}
+	if summary.Disables != 2 || summary.Enables != 1 {
+		t.Errorf("Expected 2 disables and 1 enable, got %d and %d", summary.Disables, summary.Enables)
+	}
+	if !summary.Last.Equal(now.Add(-1 * time.Minute)) {
+		t.Errorf("Expected last event at %v, got %v", now.Add(-1*time.Minute), summary.Last)
+	}
+}
+
+func TestStatsService_GetExposure(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	repo := &MockEventRepo{
+		GetRecentFunc: func(d time.Duration) []domain.Event {
+			return []domain.Event{
+				{Timestamp: now.Add(-90 * time.Minute), Type: domain.EventDisable, UpDuration: 2 * time.Second},
+				{Timestamp: now.Add(-10 * time.Minute), Type: domain.EventDisable, UpDuration: 500 * time.Millisecond},
+				{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventDisable, UpDuration: time.Second},
+				{Timestamp: now.Add(-5 * time.Minute), Type: domain.EventEnable, UpDuration: time.Hour},
+			}
+		},
+	}
+
+	exposure := services.NewStatsService(repo).GetExposureAt(context.Background(), now, 2*time.Hour)
+
+	if len(exposure.Hours) != 3 || !exposure.Hours[0].Hour.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("Expected the hours 10:00 to 12:00, got %v", exposure.Hours)
+	}
+	if exposure.Hours[1].Up != 2*time.Second || exposure.Hours[2].Up != 1500*time.Millisecond {
+		t.Errorf("Unexpected hourly exposure %v", exposure.Hours)
+	}
+	if exposure.Total != 3500*time.Millisecond || exposure.Disables != 3 || exposure.Longest != 2*time.Second {
+		t.Errorf("Unexpected exposure totals %+v", exposure)
+	}
+}
+
+func TestStatsService_GetTimeline(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	check := func(ago time.Duration, name string, status domain.Status) domain.Event {
+		return domain.Event{Timestamp: now.Add(-ago), Type: domain.EventCheck, Interface: name, Status: status}
+	}
+	repo := &MockEventRepo{
+		GetRecentFunc: func(d time.Duration) []domain.Event {
+			return []domain.Event{
+				check(45*time.Minute, "awdl0", domain.StatusUp),
+				check(44*time.Minute, "awdl0", domain.StatusDown),
+				check(30*time.Minute, "llw0", domain.StatusUp),
+				check(15*time.Minute, "awdl0", domain.StatusUp),
+			}
+		},
+	}
+
+	timeline := services.NewStatsService(repo).GetTimelineAt(context.Background(), now, "awdl0", time.Hour, 4)
+
+	want := []domain.Status{domain.StatusUnknown, domain.StatusUp, domain.StatusDown, domain.StatusUp}
+	for i := range want {
+		if timeline[i] != want[i] {
+			t.Errorf("Column %d: expected %s, got %s", i, want[i], timeline[i])
+		}
+	}
+}
+
+func TestStatsService_GetTimeline_StateBeforeWindow(t *testing.T) {
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+	var asked time.Duration
+	repo := &MockEventRepo{
+		GetRecentFunc: func(d time.Duration) []domain.Event {
+			asked = d
+			return []domain.Event{
+				{Timestamp: now.Add(-5 * time.Hour), Type: domain.EventCheck, Interface: "awdl0", Status: domain.StatusUp},
+				{Timestamp: now.Add(-3 * time.Hour), Type: domain.EventCheck, Interface: "awdl0", Status: domain.StatusDown},
+			}
+		},
+	}
+
+	timeline := services.NewStatsService(repo).GetTimelineAt(context.Background(), now, "awdl0", time.Hour, 4)
+
+	if asked <= time.Hour {
+		t.Errorf("Expected events from before the window to be asked for, got %v", asked)
+	}
+	for i, status := range timeline {
+		if status != domain.StatusDown {
+			t.Errorf("Column %d: expected the interface to stay DOWN, got %s", i, status)
+		}
+	}
+}